@@ -1,16 +1,25 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
 
 	"github.com/ahmetilboga2004/go-blog/config"
 	"github.com/ahmetilboga2004/go-blog/config/database"
 	_ "github.com/ahmetilboga2004/go-blog/docs"
 	"github.com/ahmetilboga2004/go-blog/internal/handlers"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
 	"github.com/ahmetilboga2004/go-blog/internal/middlewares"
 	"github.com/ahmetilboga2004/go-blog/internal/repository"
 	"github.com/ahmetilboga2004/go-blog/internal/services"
 	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/ahmetilboga2004/go-blog/web"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
@@ -21,9 +30,15 @@ import (
 // @BasePath /
 
 func main() {
+	config.LoadConfig()
+
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1:])
+		return
+	}
+
 	db := database.InitDB()
 
-	config.LoadConfig()
 	jwtService := services.NewJWTService(
 		config.JWT.SecretKey,
 		config.JWT.TokenExpiration,
@@ -32,19 +47,101 @@ func main() {
 	)
 	redisService := services.NewRedisService("localhost:6379", "", 0)
 
+	txManager := repository.NewTxManager(db)
+	eventBus := services.NewEventBus()
+
 	userRepo := repository.NewUserRepository(db)
-	userService := services.NewUserService(userRepo, jwtService, redisService)
+	followRepo := repository.NewFollowRepository(db)
+	userService := services.NewUserService(userRepo, followRepo, jwtService, redisService)
 	userHandler := handlers.NewUserHandler(userService)
 
+	followService := services.NewFollowService(followRepo, userRepo, eventBus)
+	followHandler := handlers.NewFollowHandler(followService)
+
 	postRepo := repository.NewPostRepository(db)
-	postService := services.NewPostService(postRepo)
+	commentRepo := repository.NewCommentRepository(db)
+	reactionRepo := repository.NewReactionRepository(db)
+	bookmarkRepo := repository.NewBookmarkRepository(db)
+
+	// Webhook deliveries are queued in the transaction of the change that
+	// triggers them, so the post and comment services enqueue them directly
+	// rather than through the event bus.
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(db)
+	webhookService := services.NewWebhookService(webhookRepo, webhookDeliveryRepo, userRepo, config.Webhook.Timeout, config.Webhook.MaxAttempts, config.Webhook.Backoff, config.Webhook.AllowPrivate)
+
+	postService := services.NewPostService(postRepo, commentRepo, reactionRepo, bookmarkRepo, txManager, eventBus, webhookService)
 	postHandler := handlers.NewPostHandler(postService)
 
-	commentRepo := repository.NewCommentRepository(db)
-	commentService := services.NewcommentService(commentRepo)
+	coAuthorRepo := repository.NewCoAuthorRepository(db)
+	collabService := services.NewCollabService(postRepo, coAuthorRepo, userRepo, txManager, config.Collab.SaveInterval, config.Collab.HistorySize, config.Realtime.BufferSize)
+	collabHandler := handlers.NewCollabHandler(collabService)
+
+	bookmarkService := services.NewBookmarkService(bookmarkRepo, postRepo, postService)
+	bookmarkHandler := handlers.NewBookmarkHandler(bookmarkService)
+
+	readingListRepo := repository.NewReadingListRepository(db)
+	readingListService := services.NewReadingListService(readingListRepo, postRepo, postService, txManager)
+	readingListHandler := handlers.NewReadingListHandler(readingListService)
+
+	spamRepo := repository.NewSpamRepository(db)
+	spamService := services.NewSpamService(spamRepo, commentRepo, txManager, config.Spam.Threshold, config.Spam.ReviewThreshold,
+		services.NewLinkCheck(config.Spam.MaxLinks),
+		services.NewBlockedWordsCheck(config.Spam.BlockedWords),
+		services.NewVelocityCheck(commentRepo, config.Spam.RateLimit, config.Spam.RateWindow),
+		services.NewBayesCheck(spamRepo),
+	)
+	spamHandler := handlers.NewSpamHandler(spamService)
+
+	commentService := services.NewcommentService(commentRepo, postRepo, reactionRepo, txManager, spamService, eventBus, webhookService, config.App.CommentMaxDepth, config.App.CommentPolicy)
 	commentHandler := handlers.NewCommentHandler(commentService)
+	moderationHandler := handlers.NewModerationHandler(commentService)
+
+	reactionService := services.NewReactionService(reactionRepo, postRepo, commentRepo, eventBus, config.App.ReactionTypes)
+	reactionHandler := handlers.NewReactionHandler(reactionService)
+
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := services.NewNotificationService(notificationRepo, commentRepo, followRepo, userRepo, txManager, eventBus)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	eventBus.Subscribe("notifications", notificationService.HandleEvent)
+
+	realtimeHub := services.NewRealtimeHub(config.Realtime.BufferSize, "localhost:6379", config.Realtime.RedisChannel)
+	eventStreamHandler := handlers.NewEventStreamHandler(realtimeHub, postService, config.Realtime.Heartbeat)
+	eventBus.Subscribe("sse", eventStreamHandler.Broadcast)
+
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	go deliverWebhooks(webhookService, config.Webhook.PollInterval)
 
-	authMiddleware := middlewares.NewAuthMiddleware(jwtService, redisService)
+	feedHandler := handlers.NewFeedHandler(postService, userService, config.App.BaseURL, config.App.Title, config.App.Description, config.App.FeedSize)
+
+	sitemapRepo := repository.NewSitemapRepository(db)
+	sitemapService := services.NewSitemapService(sitemapRepo, config.SEO.SitemapCacheTTL)
+	sitemapHandler := handlers.NewSitemapHandler(sitemapService, config.App.BaseURL, config.SEO.RobotsFile, config.SEO.RobotsDisallow)
+	eventBus.Subscribe("sitemap", sitemapService.HandleEvent)
+
+	theme, err := web.Theme(config.Web.ThemeDir, config.Web.Theme)
+	if err != nil {
+		utils.Log(utils.ERROR, "Tema yüklenemedi: %v", err)
+		os.Exit(1)
+	}
+	webHandler, err := handlers.NewWebHandler(theme, postService, commentService, userService, config.App.BaseURL, config.App.Title, config.App.Description, config.Web.PageSize, config.JWT.TokenExpiration)
+	if err != nil {
+		utils.Log(utils.ERROR, "Tema şablonları okunamadı: %v", err)
+		os.Exit(1)
+	}
+
+	searchRepo := repository.NewSearchRepository(db)
+	searchService := services.NewSearchService(searchRepo)
+	searchHandler := handlers.NewSearchHandler(searchService)
+
+	backupRepo := repository.NewBackupRepository(db, config.DB.BackupDir, backupPolicy())
+	backupService := services.NewBackupService(backupRepo)
+	backupHandler := handlers.NewBackupHandler(backupService)
+	if config.DB.BackupInterval > 0 && db.Dialect == database.SQLite {
+		go scheduleBackups(backupService, config.DB.BackupInterval)
+	}
+
+	authMiddleware := middlewares.NewAuthMiddleware(jwtService, redisService, userService)
 
 	mux := http.NewServeMux()
 
@@ -57,27 +154,308 @@ func main() {
 	mux.HandleFunc("POST /users/register", authMiddleware.GuestOnly(userHandler.Register))
 	mux.HandleFunc("POST /users/login", authMiddleware.GuestOnly(userHandler.Login))
 	mux.HandleFunc("GET /users/logout", authMiddleware.RequireLogin(userHandler.Logout))
+	mux.HandleFunc("DELETE /users/me", authMiddleware.RequireLogin(userHandler.DeleteMe))
+	mux.HandleFunc("GET /users/{id}/lists", readingListHandler.GetUserLists)
+	mux.HandleFunc("GET /users/{id}/followers", followHandler.GetFollowers)
+	mux.HandleFunc("GET /users/{id}/following", followHandler.GetFollowing)
+	mux.HandleFunc("POST /users/{id}/follow", authMiddleware.RequireLogin(followHandler.Follow))
+	mux.HandleFunc("DELETE /users/{id}/follow", authMiddleware.RequireLogin(followHandler.Unfollow))
+
+	mux.HandleFunc("GET /users/me/bookmarks", authMiddleware.RequireLogin(bookmarkHandler.GetBookmarks))
+	mux.HandleFunc("PUT /users/me/bookmarks/{postId}", authMiddleware.RequireLogin(bookmarkHandler.AddBookmark))
+	mux.HandleFunc("DELETE /users/me/bookmarks/{postId}", authMiddleware.RequireLogin(bookmarkHandler.RemoveBookmark))
+
+	mux.HandleFunc("GET /users/me/lists", authMiddleware.RequireLogin(readingListHandler.GetMyLists))
+	mux.HandleFunc("POST /users/me/lists", authMiddleware.RequireLogin(readingListHandler.Create))
+	mux.HandleFunc("PUT /users/me/lists/{id}", authMiddleware.RequireLogin(readingListHandler.UpdateList))
+	mux.HandleFunc("DELETE /users/me/lists/{id}", authMiddleware.RequireLogin(readingListHandler.DeleteList))
+	mux.HandleFunc("PUT /users/me/lists/{id}/posts/{postId}", authMiddleware.RequireLogin(readingListHandler.AddPost))
+	mux.HandleFunc("DELETE /users/me/lists/{id}/posts/{postId}", authMiddleware.RequireLogin(readingListHandler.RemovePost))
+	mux.HandleFunc("GET /lists/{id}", readingListHandler.GetList)
+	mux.HandleFunc("GET /lists/{id}/posts", readingListHandler.GetListPosts)
+
+	mux.HandleFunc("GET /feed", authMiddleware.RequireLogin(postHandler.GetFeed))
 
 	mux.HandleFunc("GET /posts", postHandler.GetAllPosts)
 	mux.HandleFunc("GET /posts/{id}", postHandler.GetPostByID)
+	mux.HandleFunc("GET /posts/{id}/comments", commentHandler.GetPostComments)
 	mux.HandleFunc("POST /posts", authMiddleware.RequireLogin(postHandler.Create))
 	mux.HandleFunc("PUT /posts/{id}", authMiddleware.RequireLogin(postHandler.UpdatePost))
 	mux.HandleFunc("DELETE /posts/{id}", authMiddleware.RequireLogin(postHandler.DeletePost))
+	mux.HandleFunc("PUT /posts/{id}/comment-settings", authMiddleware.RequireLogin(postHandler.UpdateCommentSettings))
+	mux.HandleFunc("GET /posts/{id}/coauthors", collabHandler.GetCoAuthors)
+	mux.HandleFunc("PUT /posts/{id}/coauthors/{userId}", authMiddleware.RequireLogin(collabHandler.AddCoAuthor))
+	mux.HandleFunc("DELETE /posts/{id}/coauthors/{userId}", authMiddleware.RequireLogin(collabHandler.RemoveCoAuthor))
+	mux.HandleFunc("GET /posts/{id}/collab", authMiddleware.QueryToken(authMiddleware.RequireLogin(collabHandler.Connect)))
+	mux.HandleFunc("PUT /posts/{id}/reactions/{type}", authMiddleware.RequireLogin(reactionHandler.ReactToPost))
+	mux.HandleFunc("DELETE /posts/{id}/reactions/{type}", authMiddleware.RequireLogin(reactionHandler.UnreactToPost))
 
 	mux.HandleFunc("GET /comments", commentHandler.GetAllComments)
 	mux.HandleFunc("GET /comments/{id}", commentHandler.GetCommentByID)
 	mux.HandleFunc("POST /comments", authMiddleware.RequireLogin(commentHandler.Create))
 	mux.HandleFunc("PUT /comments/{id}", authMiddleware.RequireLogin(commentHandler.UpdateComment))
 	mux.HandleFunc("DELETE /comments/{id}", authMiddleware.RequireLogin(commentHandler.DeleteComment))
+	mux.HandleFunc("PUT /comments/{id}/reactions/{type}", authMiddleware.RequireLogin(reactionHandler.ReactToComment))
+	mux.HandleFunc("DELETE /comments/{id}/reactions/{type}", authMiddleware.RequireLogin(reactionHandler.UnreactToComment))
+
+	mux.HandleFunc("GET /moderation/comments", authMiddleware.RequireModerator(moderationHandler.GetQueue))
+	mux.HandleFunc("POST /moderation/comments", authMiddleware.RequireModerator(moderationHandler.Moderate))
+
+	mux.HandleFunc("GET /notifications", authMiddleware.RequireLogin(notificationHandler.GetNotifications))
+	mux.HandleFunc("GET /notifications/unread-count", authMiddleware.RequireLogin(notificationHandler.CountUnread))
+	mux.HandleFunc("POST /notifications/{id}/read", authMiddleware.RequireLogin(notificationHandler.MarkRead))
+	mux.HandleFunc("POST /notifications/read-all", authMiddleware.RequireLogin(notificationHandler.MarkAllRead))
+	mux.HandleFunc("GET /notifications/preferences", authMiddleware.RequireLogin(notificationHandler.GetPreferences))
+	mux.HandleFunc("PUT /notifications/preferences", authMiddleware.RequireLogin(notificationHandler.UpdatePreferences))
+
+	mux.HandleFunc("GET /events", authMiddleware.QueryToken(authMiddleware.CookieToken(eventStreamHandler.Stream)))
+
+	mux.HandleFunc("GET /webhooks", authMiddleware.RequireLogin(webhookHandler.GetWebhooks))
+	mux.HandleFunc("POST /webhooks", authMiddleware.RequireLogin(webhookHandler.CreateWebhook))
+	mux.HandleFunc("GET /webhooks/{id}", authMiddleware.RequireLogin(webhookHandler.GetWebhook))
+	mux.HandleFunc("PUT /webhooks/{id}", authMiddleware.RequireLogin(webhookHandler.UpdateWebhook))
+	mux.HandleFunc("DELETE /webhooks/{id}", authMiddleware.RequireLogin(webhookHandler.DeleteWebhook))
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", authMiddleware.RequireLogin(webhookHandler.GetDeliveries))
+	mux.HandleFunc("GET /webhooks/{id}/deliveries/{deliveryId}", authMiddleware.RequireLogin(webhookHandler.GetDelivery))
+	mux.HandleFunc("POST /webhooks/{id}/deliveries/{deliveryId}/redeliver", authMiddleware.RequireLogin(webhookHandler.Redeliver))
+
+	mux.HandleFunc("GET /search", searchHandler.Search)
+
+	mux.HandleFunc("GET /sitemap.xml", sitemapHandler.Sitemap)
+	mux.HandleFunc("GET /robots.txt", sitemapHandler.Robots)
+
+	mux.HandleFunc("GET /feed.xml", feedHandler.SiteFeed)
+	mux.HandleFunc("GET /atom.xml", feedHandler.SiteFeed)
+	mux.HandleFunc("GET /feed.json", feedHandler.SiteFeed)
+	mux.HandleFunc("GET /users/{id}/feed.xml", feedHandler.AuthorFeed)
+	mux.HandleFunc("GET /users/{id}/atom.xml", feedHandler.AuthorFeed)
+	mux.HandleFunc("GET /users/{id}/feed.json", feedHandler.AuthorFeed)
+	mux.HandleFunc("GET /tags/{tag}/feed.xml", feedHandler.TagFeed)
+	mux.HandleFunc("GET /tags/{tag}/atom.xml", feedHandler.TagFeed)
+	mux.HandleFunc("GET /tags/{tag}/feed.json", feedHandler.TagFeed)
 
+	mux.HandleFunc("GET /{$}", authMiddleware.CookieToken(webHandler.Home))
+	mux.HandleFunc("GET /post/{id}", authMiddleware.CookieToken(webHandler.Post))
+	mux.HandleFunc("POST /post/{id}/comments", authMiddleware.CookieToken(webHandler.CreateComment))
+	mux.HandleFunc("GET /author/{id}", authMiddleware.CookieToken(webHandler.Author))
+	mux.HandleFunc("GET /tag/{tag}", authMiddleware.CookieToken(webHandler.Tag))
+	mux.HandleFunc("GET /login", authMiddleware.CookieToken(webHandler.LoginForm))
+	mux.HandleFunc("POST /login", authMiddleware.CookieToken(webHandler.Login))
+	mux.HandleFunc("GET /register", authMiddleware.CookieToken(webHandler.RegisterForm))
+	mux.HandleFunc("POST /register", authMiddleware.CookieToken(webHandler.Register))
+	mux.HandleFunc("POST /logout", authMiddleware.CookieToken(webHandler.Logout))
+	mux.HandleFunc("GET /static/", webHandler.Static)
+
+	mux.HandleFunc("GET /admin/backups", authMiddleware.RequireAdmin(backupHandler.GetAllBackups))
+	mux.HandleFunc("POST /admin/backups", authMiddleware.RequireAdmin(backupHandler.Create))
+	mux.HandleFunc("GET /admin/spam", authMiddleware.RequireAdmin(spamHandler.GetStats))
+	mux.HandleFunc("POST /admin/spam/retrain", authMiddleware.RequireAdmin(spamHandler.Retrain))
+	mux.HandleFunc("GET /admin/spam/comments/{id}", authMiddleware.RequireAdmin(spamHandler.InspectComment))
+
+	// Streams stay open and a backup of a large database can take longer
+	// than any request timeout, so they get no deadline.
 	server := &http.Server{
 		Addr:    ":4000",
-		Handler: authMux,
+		Handler: middlewares.Timeout(config.App.RequestTimeout, authMux, "GET /events", "GET /posts/{id}/collab", "POST /admin/backups"),
 	}
 	utils.Log(utils.INFO, "Sunucu başlatılıyor...")
-	err := server.ListenAndServe()
+	err = server.ListenAndServe()
 	if err != nil {
 		utils.Log(utils.ERROR, "Sunucu başlatılırken bir hata oluştu: %v", err)
 	}
 
 }
+
+func backupPolicy() database.RetentionPolicy {
+	return database.RetentionPolicy{Keep: config.DB.BackupKeep, MaxAge: config.DB.BackupMaxAge}
+}
+
+func scheduleBackups(backupService interfaces.BackupService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		backup, err := backupService.CreateBackup(context.Background())
+		if err != nil {
+			utils.Log(utils.WARNING, "Otomatik yedekleme başarısız: %v", err)
+			continue
+		}
+		utils.Log(utils.INFO, "Otomatik yedek alındı: %s", backup.Name)
+	}
+}
+
+func deliverWebhooks(webhookService interfaces.WebhookService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := webhookService.DeliverDue(context.Background()); err != nil {
+			utils.Log(utils.WARNING, "Webhook gönderimi başarısız: %v", err)
+		}
+	}
+}
+
+func runCommand(args []string) {
+	switch args[0] {
+	case "migrate":
+		db := database.Open()
+		defer db.Close()
+		if err := migrateCommand(db, args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "backup":
+		db := database.Open()
+		defer db.Close()
+		if err := backupCommand(db, args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "restore":
+		if err := restoreCommand(args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "user":
+		db := database.Open()
+		defer db.Close()
+		if err := userCommand(db, args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	default:
+		printUsage()
+		os.Exit(2)
+	}
+}
+
+func migrateCommand(db *database.DB, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "up":
+		applied, err := database.Migrate(db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migration(s) applied\n", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid step count: %s", args[1])
+			}
+			steps = n
+		}
+		reverted, err := database.Rollback(db, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migration(s) rolled back\n", reverted)
+	case "status":
+		statuses, err := database.Status(db)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt := "pending", "-"
+			if s.Applied {
+				state, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		tw.Flush()
+	default:
+		return fmt.Errorf("unknown migrate action: %s (expected up, down or status)", action)
+	}
+	return nil
+}
+
+func backupCommand(db *database.DB, args []string) error {
+	action := "create"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "create":
+		backup, err := database.CreateBackup(context.Background(), db, config.DB.BackupDir, backupPolicy())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("backup written to %s (%d bytes)\n", backup.Path, backup.Size)
+	case "list":
+		backups, err := database.ListBackups(config.DB.BackupDir)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tSIZE\tCREATED AT")
+		for _, b := range backups {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", b.Name, b.Size, b.CreatedAt.Format(time.RFC3339))
+		}
+		tw.Flush()
+	case "prune":
+		if err := database.PruneBackups(config.DB.BackupDir, backupPolicy()); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown backup action: %s (expected create, list or prune)", action)
+	}
+	return nil
+}
+
+// restoreCommand swaps the database file for a verified backup. It works on
+// files directly, so the server must not be running.
+func restoreCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: blog restore <backup file or name>")
+	}
+	if database.Dialect(config.DB.Driver) != database.SQLite {
+		return database.ErrBackupUnsupported
+	}
+
+	source := args[0]
+	if _, err := os.Stat(source); err != nil {
+		source = filepath.Join(config.DB.BackupDir, args[0])
+	}
+	previous, err := database.RestoreBackup(context.Background(), source, config.DB.Path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("restored %s from %s\n", config.DB.Path, source)
+	if previous != "" {
+		fmt.Printf("previous database saved as %s\n", previous)
+	}
+	return nil
+}
+
+func userCommand(db *database.DB, args []string) error {
+	if len(args) != 3 || args[0] != "role" {
+		return fmt.Errorf("usage: blog user role <username or email> <user|moderator|admin>")
+	}
+	userService := services.NewUserService(repository.NewUserRepository(db), nil, nil, nil)
+	user, err := userService.SetUserRole(context.Background(), args[1], args[2])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s is now %s\n", user.Username, user.Role)
+	return nil
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, `Usage:
+  blog                         start the HTTP server
+  blog migrate [up]            apply pending migrations
+  blog migrate down [steps]    roll back the last migration(s), default 1
+  blog migrate status          list migrations and whether they are applied
+  blog backup [create]         snapshot the database into BACKUP_DIR and prune old backups
+  blog backup list             list stored backups
+  blog backup prune            delete backups outside the retention policy
+  blog restore <file>          verify a backup and swap it in (stop the server first)
+  blog user role <user> <role> set a user's role (user, moderator or admin)`)
+}
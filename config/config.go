@@ -3,23 +3,52 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type appConfig struct {
-	Port    string
-	Mode    string
-	BaseURL string
+	Port           string
+	Mode           string
+	BaseURL        string
+	RequestTimeout time.Duration
+
+	// Title and Description describe the blog in its feeds.
+	Title       string
+	Description string
+	FeedSize    int
+
+	CommentMaxDepth int
+	CommentPolicy   string
+	ReactionTypes   []string
 }
 
 type dbConfig struct {
+	Driver   string
 	Host     string
 	Port     string
 	Username string
 	Password string
 	Name     string
+	SSLMode  string
+
+	// SQLite only.
+	Path        string
+	JournalMode string
+	Synchronous string
+	BusyTimeout time.Duration
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	BackupDir      string
+	BackupKeep     int
+	BackupMaxAge   time.Duration
+	BackupInterval time.Duration
 }
 
 type jwtConfig struct {
@@ -29,6 +58,55 @@ type jwtConfig struct {
 	VerificationTokenExpiration time.Duration
 }
 
+type spamConfig struct {
+	Threshold       float64
+	ReviewThreshold float64
+	MaxLinks        int
+	BlockedWords    []string
+	RateLimit       int
+	RateWindow      time.Duration
+}
+
+// realtimeConfig controls the Server-Sent Events hub. With a Redis channel
+// set, events are fanned out through Redis so every instance receives them.
+type realtimeConfig struct {
+	RedisChannel string
+	BufferSize   int
+	Heartbeat    time.Duration
+}
+
+// collabConfig controls collaborative draft editing.
+type collabConfig struct {
+	SaveInterval time.Duration
+	HistorySize  int
+}
+
+// webhookConfig controls outgoing webhook deliveries. Failed deliveries are
+// retried MaxAttempts times, waiting Backoff, then twice as long each time.
+type webhookConfig struct {
+	PollInterval time.Duration
+	Timeout      time.Duration
+	MaxAttempts  int
+	Backoff      time.Duration
+	AllowPrivate bool
+}
+
+// seoConfig controls robots.txt and the sitemap. RobotsFile, when set,
+// replaces the generated robots.txt.
+type seoConfig struct {
+	RobotsFile      string
+	RobotsDisallow  []string
+	SitemapCacheTTL time.Duration
+}
+
+// webConfig controls the HTML frontend. ThemeDir, when set, is searched for
+// the Theme directory instead of the themes built into the binary.
+type webConfig struct {
+	Theme    string
+	ThemeDir string
+	PageSize int
+}
+
 type smtpConfig struct {
 	Host     string
 	Port     string
@@ -38,10 +116,16 @@ type smtpConfig struct {
 }
 
 var (
-	App  *appConfig
-	DB   *dbConfig
-	JWT  *jwtConfig
-	SMTP *smtpConfig
+	App      *appConfig
+	DB       *dbConfig
+	JWT      *jwtConfig
+	SMTP     *smtpConfig
+	Spam     *spamConfig
+	Realtime *realtimeConfig
+	Collab   *collabConfig
+	Webhook  *webhookConfig
+	SEO      *seoConfig
+	Web      *webConfig
 )
 
 func LoadConfig() {
@@ -51,17 +135,42 @@ func LoadConfig() {
 	}
 
 	App = &appConfig{
-		Port:    getEnv("APP_PORT"),
-		Mode:    getEnv("APP_MODE"),
-		BaseURL: getEnv("APP_BASE_URL"),
+		Port:           getEnv("APP_PORT"),
+		Mode:           getEnv("APP_MODE"),
+		BaseURL:        getEnv("APP_BASE_URL"),
+		RequestTimeout: getEnvAsDuration("APP_REQUEST_TIMEOUT", "10s"),
+
+		Title:       getEnvOrDefault("APP_TITLE", "Go Blog"),
+		Description: getEnvOrDefault("APP_DESCRIPTION", ""),
+		FeedSize:    getEnvAsInt("FEED_SIZE", 20),
+
+		CommentMaxDepth: getEnvAsInt("COMMENT_MAX_DEPTH", 5),
+		CommentPolicy:   getEnvOrDefault("COMMENT_POLICY", "auto"),
+		ReactionTypes:   getEnvAsList("REACTION_TYPES", "heart,laugh,hooray,confused,rocket,eyes"),
 	}
 
 	DB = &dbConfig{
+		Driver:   getEnvOrDefault("DB_DRIVER", "sqlite"),
 		Host:     getEnv("DB_HOST"),
 		Port:     getEnv("DB_PORT"),
 		Username: getEnv("DB_USERNAME"),
 		Password: getEnv("DB_PASSWORD"),
 		Name:     getEnv("DB_NAME"),
+		SSLMode:  getEnvOrDefault("DB_SSLMODE", "disable"),
+
+		Path:        getEnvOrDefault("DB_PATH", "blog.db"),
+		JournalMode: getEnvOrDefault("DB_JOURNAL_MODE", "WAL"),
+		Synchronous: getEnvOrDefault("DB_SYNCHRONOUS", "NORMAL"),
+		BusyTimeout: getEnvAsDuration("DB_BUSY_TIMEOUT", "5s"),
+
+		MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 10),
+		MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
+		ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", "1h"),
+
+		BackupDir:      getEnvOrDefault("BACKUP_DIR", "backups"),
+		BackupKeep:     getEnvAsInt("BACKUP_KEEP", 7),
+		BackupMaxAge:   getEnvAsDuration("BACKUP_MAX_AGE", "0"),
+		BackupInterval: getEnvAsDuration("BACKUP_INTERVAL", "0"),
 	}
 
 	JWT = &jwtConfig{
@@ -71,6 +180,46 @@ func LoadConfig() {
 		VerificationTokenExpiration: getEnvAsDuration("JWT_VERIFICATION_TOKEN_EXPIRATION", "1440m"),
 	}
 
+	Spam = &spamConfig{
+		Threshold:       getEnvAsFloat("SPAM_THRESHOLD", 0.9),
+		ReviewThreshold: getEnvAsFloat("SPAM_REVIEW_THRESHOLD", 0.5),
+		MaxLinks:        getEnvAsInt("SPAM_MAX_LINKS", 3),
+		BlockedWords:    getEnvAsList("SPAM_BLOCKED_WORDS", ""),
+		RateLimit:       getEnvAsInt("SPAM_RATE_LIMIT", 5),
+		RateWindow:      getEnvAsDuration("SPAM_RATE_WINDOW", "1m"),
+	}
+
+	Realtime = &realtimeConfig{
+		RedisChannel: getEnvOrDefault("REALTIME_REDIS_CHANNEL", ""),
+		BufferSize:   getEnvAsInt("REALTIME_BUFFER_SIZE", 64),
+		Heartbeat:    getEnvAsDuration("REALTIME_HEARTBEAT", "25s"),
+	}
+
+	Collab = &collabConfig{
+		SaveInterval: getEnvAsDuration("COLLAB_SAVE_INTERVAL", "10s"),
+		HistorySize:  getEnvAsInt("COLLAB_HISTORY_SIZE", 500),
+	}
+
+	Webhook = &webhookConfig{
+		PollInterval: getEnvAsDuration("WEBHOOK_POLL_INTERVAL", "5s"),
+		Timeout:      getEnvAsDuration("WEBHOOK_TIMEOUT", "10s"),
+		MaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		Backoff:      getEnvAsDuration("WEBHOOK_BACKOFF", "30s"),
+		AllowPrivate: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE", false),
+	}
+
+	SEO = &seoConfig{
+		RobotsFile:      getEnvOrDefault("ROBOTS_FILE", ""),
+		RobotsDisallow:  getEnvAsList("ROBOTS_DISALLOW", "/admin/,/moderation/,/swagger/"),
+		SitemapCacheTTL: getEnvAsDuration("SITEMAP_CACHE_TTL", "1h"),
+	}
+
+	Web = &webConfig{
+		Theme:    getEnvOrDefault("APP_THEME", "default"),
+		ThemeDir: getEnvOrDefault("APP_THEME_DIR", ""),
+		PageSize: getEnvAsInt("WEB_PAGE_SIZE", 10),
+	}
+
 	SMTP = &smtpConfig{
 		Host:     getEnv("SMTP_HOST"),
 		Port:     getEnv("SMTP_PORT"),
@@ -88,21 +237,64 @@ func getEnv(key string) string {
 	return ""
 }
 
-/* func getEnvAsInt(key string) int {
+func getEnvOrDefault(key, defaultVal string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultVal
+}
+
+func getEnvAsInt(key string, defaultVal int) int {
 	if value, exists := os.LookupEnv(key); exists {
 		if intValue, err := strconv.Atoi(value); err == nil {
 			return intValue
 		}
+		log.Fatalf("Environment variable %s must be an integer", key)
 	}
-	log.Fatalf("Environment variable %s must be an integer", key)
-	return 0
-} */
+	return defaultVal
+}
+
+func getEnvAsBool(key string, defaultVal bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+		log.Fatalf("Environment variable %s must be a boolean", key)
+	}
+	return defaultVal
+}
+
+func getEnvAsFloat(key string, defaultVal float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+		log.Fatalf("Environment variable %s must be a number", key)
+	}
+	return defaultVal
+}
+
+// getEnvAsList splits a comma separated variable, dropping empty entries.
+func getEnvAsList(key, defaultVal string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		value = defaultVal
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
 
 func getEnvAsDuration(key, defaultVal string) time.Duration {
 	if value, exists := os.LookupEnv(key); exists {
 		if duration, err := time.ParseDuration(value); err == nil {
 			return duration
 		}
+		log.Fatalf("Environment variable %s must be a duration", key)
 	}
 	duration, _ := time.ParseDuration(defaultVal)
 	return duration
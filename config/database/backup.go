@@ -0,0 +1,235 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+)
+
+const (
+	backupPrefix     = "blog-"
+	backupSuffix     = ".db"
+	backupTimeLayout = "20060102T150405Z"
+)
+
+type Backup struct {
+	Name      string
+	Path      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// RetentionPolicy limits how many backups are kept. Keep is the number of
+// newest backups to keep and MaxAge drops anything older; zero disables
+// either limit.
+type RetentionPolicy struct {
+	Keep   int
+	MaxAge time.Duration
+}
+
+var ErrBackupUnsupported = errors.New("backups are only supported for sqlite; use pg_dump for postgres")
+
+// CreateBackup writes a consistent snapshot of the live database into dir
+// with VACUUM INTO, which is safe while the server keeps serving requests,
+// and then prunes old backups according to policy.
+func CreateBackup(ctx context.Context, db *DB, dir string, policy RetentionPolicy) (*Backup, error) {
+	if db.Dialect != SQLite {
+		return nil, ErrBackupUnsupported
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	name := backupPrefix + createdAt.Format(backupTimeLayout) + backupSuffix
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return nil, errors.New("a backup was already taken this second")
+	}
+	tmpPath := path + ".tmp"
+	os.Remove(tmpPath)
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("backup failed: %w", err)
+	}
+	if err := checkIntegrity(ctx, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := PruneBackups(dir, policy); err != nil {
+		utils.Log(utils.WARNING, "Failed to prune old backups: %v", err)
+	}
+	return &Backup{Name: name, Path: path, Size: info.Size(), CreatedAt: createdAt}, nil
+}
+
+// ListBackups returns the backups in dir, newest first.
+func ListBackups(dir string) ([]*Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []*Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+		createdAt, err := time.Parse(backupTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupSuffix))
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, &Backup{
+			Name:      name,
+			Path:      filepath.Join(dir, name),
+			Size:      info.Size(),
+			CreatedAt: createdAt,
+		})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// PruneBackups deletes the backups in dir that fall outside policy.
+func PruneBackups(dir string, policy RetentionPolicy) error {
+	backups, err := ListBackups(dir)
+	if err != nil {
+		return err
+	}
+	for i, backup := range backups {
+		tooMany := policy.Keep > 0 && i >= policy.Keep
+		tooOld := policy.MaxAge > 0 && time.Since(backup.CreatedAt) > policy.MaxAge
+		// Never delete the newest backup because of its age alone.
+		if tooMany || (tooOld && i > 0) {
+			if err := os.Remove(backup.Path); err != nil {
+				return err
+			}
+			utils.Log(utils.INFO, "Removed old backup %s", backup.Name)
+		}
+	}
+	return nil
+}
+
+// RestoreBackup replaces the database file at target with the backup at
+// source. The backup must pass an integrity check first. The current
+// database is kept next to it with a .pre-restore suffix. The server must be
+// stopped while restoring.
+func RestoreBackup(ctx context.Context, source, target string) (string, error) {
+	if err := checkIntegrity(ctx, source); err != nil {
+		return "", err
+	}
+
+	tmpPath := target + ".restore"
+	if err := copyFile(source, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+
+	var previous string
+	if _, err := os.Stat(target); err == nil {
+		// Fold the WAL into the old file so the saved copy is complete.
+		if err := checkpoint(ctx, target); err != nil {
+			os.Remove(tmpPath)
+			return "", err
+		}
+		previous = target + ".pre-restore-" + time.Now().UTC().Format(backupTimeLayout)
+		if err := os.Rename(target, previous); err != nil {
+			os.Remove(tmpPath)
+			return "", err
+		}
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		os.Remove(target + suffix)
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		return "", err
+	}
+	return previous, nil
+}
+
+// checkIntegrity opens the file read-only and runs PRAGMA integrity_check.
+// It also makes sure the file is one of our databases.
+func checkIntegrity(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("integrity check of %s failed: %w", path, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("integrity check of %s failed: %s", path, result)
+	}
+
+	var tables int
+	err = db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`).Scan(&tables)
+	if err != nil {
+		return err
+	}
+	if tables == 0 {
+		return fmt.Errorf("%s is not a blog database", path)
+	}
+	return nil
+}
+
+func checkpoint(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	_, err = db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
+	return err
+}
+
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,234 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// openBlogDB opens a migrated SQLite database at path with a notes table to
+// tell snapshots apart.
+func openBlogDB(t *testing.T, path string) *DB {
+	t.Helper()
+	db, err := Connect(SQLite, SQLiteDSN(path, "WAL", "NORMAL", 5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("CREATE TABLE IF NOT EXISTS notes (body TEXT NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func addNote(t *testing.T, db *DB, body string) {
+	t.Helper()
+	if _, err := db.Exec("INSERT INTO notes (body) VALUES (?)", body); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// notes returns the notes stored in the database file at path.
+func notes(t *testing.T, path string) []string {
+	t.Helper()
+	db, err := Connect(SQLite, "file:"+path+"?mode=ro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	rows, err := db.Query("SELECT body FROM notes ORDER BY rowid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var bodies []string
+	for rows.Next() {
+		var body string
+		if err := rows.Scan(&body); err != nil {
+			t.Fatal(err)
+		}
+		bodies = append(bodies, body)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return bodies
+}
+
+func TestCreateBackup(t *testing.T) {
+	ctx := context.Background()
+	db := openBlogDB(t, filepath.Join(t.TempDir(), "blog.db"))
+	addNote(t, db, "first")
+	dir := filepath.Join(t.TempDir(), "backups")
+
+	backup, err := CreateBackup(ctx, db, dir, RetentionPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := notes(t, backup.Path); !slices.Equal(got, []string{"first"}) {
+		t.Errorf("backup holds %v, want [first]", got)
+	}
+	if err := checkIntegrity(ctx, backup.Path); err != nil {
+		t.Errorf("backup fails its integrity check: %v", err)
+	}
+	if _, err := os.Stat(backup.Path + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	backups, err := ListBackups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 || backups[0].Name != backup.Name || backups[0].Size != backup.Size {
+		t.Errorf("ListBackups = %v, want the new backup", backups)
+	}
+
+	if _, err := CreateBackup(ctx, &DB{Dialect: Postgres}, dir, RetentionPolicy{}); !errors.Is(err, ErrBackupUnsupported) {
+		t.Errorf("postgres backup = %v, want %v", err, ErrBackupUnsupported)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	ages := []time.Duration{time.Hour, 2 * time.Hour, 48 * time.Hour, 72 * time.Hour}
+	name := func(age time.Duration) string {
+		return backupPrefix + now.Add(-age).Format(backupTimeLayout) + backupSuffix
+	}
+
+	tests := []struct {
+		name   string
+		policy RetentionPolicy
+		ages   []time.Duration
+		want   []time.Duration
+	}{
+		{"no limits", RetentionPolicy{}, ages, ages},
+		{"keep", RetentionPolicy{Keep: 2}, ages, ages[:2]},
+		{"max age", RetentionPolicy{MaxAge: 24 * time.Hour}, ages, ages[:2]},
+		{"both", RetentionPolicy{Keep: 1, MaxAge: 24 * time.Hour}, ages, ages[:1]},
+		// The newest backup survives its age so there's always one left.
+		{"all too old", RetentionPolicy{MaxAge: 24 * time.Hour}, ages[2:], ages[2:3]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, age := range tt.ages {
+				if err := os.WriteFile(filepath.Join(dir, name(age)), nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Files that aren't backups are never touched.
+			other := filepath.Join(dir, "blog-notes.txt")
+			if err := os.WriteFile(other, nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := PruneBackups(dir, tt.policy); err != nil {
+				t.Fatal(err)
+			}
+			backups, err := ListBackups(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, backup := range backups {
+				got = append(got, backup.Name)
+			}
+			var want []string
+			for _, age := range tt.want {
+				want = append(want, name(age))
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("kept %v, want %v", got, want)
+			}
+			if _, err := os.Stat(other); err != nil {
+				t.Errorf("pruning removed another file: %v", err)
+			}
+		})
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	target := filepath.Join(dir, "blog.db")
+	db := openBlogDB(t, target)
+	addNote(t, db, "backed up")
+	backup, err := CreateBackup(ctx, db, filepath.Join(dir, "backups"), RetentionPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addNote(t, db, "after the backup")
+	db.Close()
+
+	previous, err := RestoreBackup(ctx, backup.Path, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := notes(t, target); !slices.Equal(got, []string{"backed up"}) {
+		t.Errorf("restored database holds %v, want [backed up]", got)
+	}
+	// The replaced database is kept next to the restored one.
+	if !strings.HasPrefix(previous, target+".pre-restore-") {
+		t.Fatalf("previous database saved as %q", previous)
+	}
+	if got := notes(t, previous); !slices.Equal(got, []string{"backed up", "after the backup"}) {
+		t.Errorf("previous database holds %v", got)
+	}
+	if _, err := os.Stat(target + "-wal"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("old WAL left next to the restored database: %v", err)
+	}
+
+	// Restoring where there is no database yet leaves nothing to keep.
+	fresh := filepath.Join(dir, "fresh.db")
+	if previous, err := RestoreBackup(ctx, backup.Path, fresh); err != nil || previous != "" {
+		t.Errorf("restore into a new file = %q, %v", previous, err)
+	}
+}
+
+func TestRestoreBackupChecksIntegrity(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	target := filepath.Join(dir, "blog.db")
+	db := openBlogDB(t, target)
+	addNote(t, db, "live")
+	db.Close()
+
+	garbage := filepath.Join(dir, "garbage.db")
+	if err := os.WriteFile(garbage, []byte(strings.Repeat("not a database ", 512)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A healthy SQLite file that isn't one of ours.
+	foreign := filepath.Join(dir, "foreign.db")
+	other, err := Connect(SQLite, SQLiteDSN(foreign, "DELETE", "NORMAL", time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Exec("CREATE TABLE things (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	other.Close()
+
+	for _, source := range []string{garbage, foreign, filepath.Join(dir, "missing.db")} {
+		if _, err := RestoreBackup(ctx, source, target); err == nil {
+			t.Errorf("restoring %s succeeded", filepath.Base(source))
+		}
+	}
+	if got := notes(t, target); !slices.Equal(got, []string{"live"}) {
+		t.Errorf("database after failed restores holds %v, want [live]", got)
+	}
+	matches, err := filepath.Glob(target + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("failed restores left %v behind", matches)
+	}
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	benchmarkPosts     = 200
+	benchmarkWriteRate = 0.2 // share of operations that insert a comment
+)
+
+// BenchmarkConcurrentLoad mixes comment inserts with the reads the API serves
+// most, a post's comments and an author's posts, under each journal mode.
+// Raise -cpu to add concurrent workers.
+func BenchmarkConcurrentLoad(b *testing.B) {
+	for _, journalMode := range []string{"WAL", "DELETE"} {
+		b.Run(journalMode, func(b *testing.B) {
+			db := openTestSQLite(b, journalMode)
+			db.SetMaxOpenConns(10)
+			if _, err := Migrate(db); err != nil {
+				b.Fatal(err)
+			}
+			ctx := context.Background()
+			userID, postIDs, err := seedBenchmark(ctx, db, benchmarkPosts)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			var seed atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				rng := rand.New(rand.NewSource(seed.Add(1)))
+				for pb.Next() {
+					postID := postIDs[rng.Intn(len(postIDs))]
+					var err error
+					if rng.Float64() < benchmarkWriteRate {
+						err = benchmarkWrite(ctx, db, userID, postID)
+					} else {
+						err = benchmarkRead(ctx, db, userID, postID)
+					}
+					if err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func seedBenchmark(ctx context.Context, db *DB, posts int) (uuid.UUID, []uuid.UUID, error) {
+	userID := uuid.New()
+	postIDs := make([]uuid.UUID, posts)
+	err := db.WithinTx(ctx, func(ctx context.Context) error {
+		now := time.Now().UTC()
+		name := "bench_" + userID.String()[:8]
+		_, err := db.ExecContext(ctx, `INSERT INTO users (id, firstName, lastName, username, email, password, salt, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, '', '', ?, ?)`, userID, "Bench", "User", name, name+"@example.com", now, now)
+		if err != nil {
+			return err
+		}
+		for i := range postIDs {
+			postIDs[i] = uuid.New()
+			_, err := db.ExecContext(ctx, `INSERT INTO posts (id, title, content, content_html, status, user_id, created_at, updated_at)
+				VALUES (?, ?, ?, '', 'published', ?, ?, ?)`, postIDs[i], fmt.Sprintf("Benchmark post %d", i), "Benchmark content", userID, now, now)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return userID, postIDs, err
+}
+
+func benchmarkWrite(ctx context.Context, db *DB, userID, postID uuid.UUID) error {
+	return db.WithinTx(ctx, func(ctx context.Context) error {
+		now := time.Now().UTC()
+		_, err := db.ExecContext(ctx, `INSERT INTO comments (id, content, post_id, user_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			uuid.New(), "Benchmark comment", postID, userID, now, now)
+		return err
+	})
+}
+
+func benchmarkRead(ctx context.Context, db *DB, userID, postID uuid.UUID) error {
+	if err := drainRows(ctx, db, `SELECT id, content FROM comments WHERE post_id = ? ORDER BY created_at DESC LIMIT 20`, postID); err != nil {
+		return err
+	}
+	return drainRows(ctx, db, `SELECT id, title FROM posts WHERE user_id = ? ORDER BY created_at DESC LIMIT 20`, userID)
+}
+
+func drainRows(ctx context.Context, db *DB, query string, arg any) error {
+	rows, err := db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var text string
+		if err := rows.Scan(&id, &text); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
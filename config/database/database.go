@@ -0,0 +1,87 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// Open connects to the database selected by DB_DRIVER without touching the
+// schema.
+func Open() *DB {
+	dialect, dsn := dataSource()
+	db, err := Connect(dialect, dsn)
+	if err != nil {
+		utils.Log(utils.ERROR, "Database connection failed: %v", err)
+	}
+	db.SetMaxOpenConns(config.DB.MaxOpenConns)
+	db.SetMaxIdleConns(config.DB.MaxIdleConns)
+	db.SetConnMaxLifetime(config.DB.ConnMaxLifetime)
+	if err = db.Ping(); err != nil {
+		utils.Log(utils.ERROR, "Database ping failed: %v", err)
+	}
+
+	utils.Log(utils.INFO, "Database connection successfully (%s)", dialect)
+	return db
+}
+
+// Connect opens a connection pool for the dialect without checking that the
+// database is reachable.
+func Connect(dialect Dialect, dsn string) (*DB, error) {
+	driverName := "sqlite"
+	if dialect == Postgres {
+		driverName = "pgx"
+	}
+	sqlDB, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{DB: sqlDB, Dialect: dialect}, nil
+}
+
+func InitDB() *DB {
+	db := Open()
+	applied, err := Migrate(db)
+	if err != nil {
+		utils.Log(utils.ERROR, "Database migration failed: %v", err)
+		return db
+	}
+	utils.Log(utils.INFO, "Database schema is up to date (%d migrations applied)", applied)
+	return db
+}
+
+func dataSource() (Dialect, string) {
+	switch Dialect(config.DB.Driver) {
+	case Postgres:
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(config.DB.Username, config.DB.Password),
+			Host:     fmt.Sprintf("%s:%s", config.DB.Host, config.DB.Port),
+			Path:     config.DB.Name,
+			RawQuery: url.Values{"sslmode": {config.DB.SSLMode}}.Encode(),
+		}
+		return Postgres, dsn.String()
+	default:
+		return SQLite, SQLiteDSN(config.DB.Path, config.DB.JournalMode, config.DB.Synchronous, config.DB.BusyTimeout)
+	}
+}
+
+// SQLiteDSN applies the given pragmas to every pooled connection.
+// Transactions take the write lock up front so two of them can't deadlock
+// upgrading from a read lock; WithinTx retries when the lock stays busy.
+func SQLiteDSN(path, journalMode, synchronous string, busyTimeout time.Duration) string {
+	params := url.Values{}
+	params.Set("_time_format", "sqlite")
+	params.Set("_txlock", "immediate")
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	params.Add("_pragma", fmt.Sprintf("journal_mode(%s)", journalMode))
+	params.Add("_pragma", fmt.Sprintf("synchronous(%s)", synchronous))
+	return "file:" + path + "?" + params.Encode()
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// DB wraps *sql.DB so repositories can write queries once with ? placeholders
+// and run them against either database. The *Context methods run inside the
+// transaction carried by ctx when there is one (see WithinTx).
+type DB struct {
+	*sql.DB
+	Dialect Dialect
+}
+
+func (db *DB) Query(query string, args ...any) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+func (db *DB) QueryRow(query string, args ...any) *sql.Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.QueryContext(ctx, db.Rebind(query), args...)
+	}
+	return db.DB.QueryContext(ctx, db.Rebind(query), args...)
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.QueryRowContext(ctx, db.Rebind(query), args...)
+	}
+	return db.DB.QueryRowContext(ctx, db.Rebind(query), args...)
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.ExecContext(ctx, db.Rebind(query), args...)
+	}
+	return db.DB.ExecContext(ctx, db.Rebind(query), args...)
+}
+
+// Rebind converts ? placeholders into the dialect's bind variable syntax.
+func (db *DB) Rebind(query string) string {
+	return rebind(db.Dialect, query)
+}
+
+func rebind(dialect Dialect, query string) string {
+	if dialect != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	inString := false
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			inString = !inString
+			b.WriteRune(r)
+		case r == '?' && !inString:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
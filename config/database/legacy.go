@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+)
+
+// adoptLegacySchema upgrades a database created by the old createTables
+// bootstrap, which had no schema_migrations table, to the shape of the first
+// migration so the runner can take over from there.
+func adoptLegacySchema(conn *sql.Conn) error {
+	ctx := context.Background()
+	var versions, users int
+	if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migrations").Scan(&versions); err != nil {
+		return err
+	}
+	err := conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'users'`).Scan(&users)
+	if err != nil {
+		return err
+	}
+	if versions > 0 || users == 0 {
+		return nil
+	}
+
+	utils.Log(utils.INFO, "Upgrading legacy schema before running migrations")
+	// Databases created before the timestamp columns existed still need them.
+	for _, table := range []string{"users", "posts", "comments"} {
+		if _, err := ensureColumn(conn, table, "created_at", "DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'"); err != nil {
+			utils.Log(utils.ERROR, "Failed to add created_at to %s, error: %v", table, err)
+			return err
+		}
+		added, err := ensureColumn(conn, table, "updated_at", "DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'")
+		if err != nil {
+			utils.Log(utils.ERROR, "Failed to add updated_at to %s, error: %v", table, err)
+			return err
+		}
+		if added {
+			if _, err := conn.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET updated_at = created_at", table)); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := ensureColumn(conn, "posts", "status", "TEXT NOT NULL DEFAULT 'published'"); err != nil {
+		utils.Log(utils.ERROR, "Failed to add status to posts, error: %v", err)
+		return err
+	}
+	added, err := ensureColumn(conn, "posts", "content_html", "TEXT NOT NULL DEFAULT ''")
+	if err != nil {
+		utils.Log(utils.ERROR, "Failed to add content_html to posts, error: %v", err)
+		return err
+	}
+	if added {
+		if err := renderExistingPosts(conn); err != nil {
+			utils.Log(utils.ERROR, "Failed to render existing posts, error: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureColumn adds the column when it is missing and reports whether it did.
+func ensureColumn(conn *sql.Conn, table, column, definition string) (bool, error) {
+	ctx := context.Background()
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return false, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	if _, err = conn.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// renderExistingPosts fills content_html for posts written as plain text
+// before Markdown rendering existed.
+func renderExistingPosts(conn *sql.Conn) error {
+	ctx := context.Background()
+	rows, err := conn.QueryContext(ctx, "SELECT id, content FROM posts")
+	if err != nil {
+		return err
+	}
+	contents := make(map[string]string)
+	for rows.Next() {
+		var id string
+		var content sql.NullString
+		if err := rows.Scan(&id, &content); err != nil {
+			rows.Close()
+			return err
+		}
+		contents[id] = content.String
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, content := range contents {
+		html, err := utils.RenderMarkdown(content)
+		if err != nil {
+			return err
+		}
+		if _, err := conn.ExecContext(ctx, "UPDATE posts SET content_html = ? WHERE id = ?", html, id); err != nil {
+			return err
+		}
+	}
+	utils.Log(utils.INFO, "Rendered Markdown for %d existing posts", len(contents))
+	return nil
+}
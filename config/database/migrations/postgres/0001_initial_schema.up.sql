@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS users (
+	id UUID PRIMARY KEY,
+	firstName TEXT NOT NULL,
+	lastName TEXT NOT NULL,
+	username TEXT NOT NULL UNIQUE,
+	email TEXT NOT NULL UNIQUE,
+	password TEXT NOT NULL,
+	salt TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS posts (
+	id UUID PRIMARY KEY,
+	title TEXT NOT NULL,
+	content TEXT,
+	content_html TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'published',
+	user_id UUID REFERENCES users(id),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+	id UUID PRIMARY KEY,
+	content TEXT,
+	post_id UUID REFERENCES posts(id),
+	user_id UUID REFERENCES users(id),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,13 @@
+-- Title matches weigh more than body matches, like the SQLite bm25 weights.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+	setweight(to_tsvector('simple', coalesce(content, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS posts_search_idx ON posts USING GIN (search);
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (
+	to_tsvector('simple', coalesce(content, ''))
+) STORED;
+
+CREATE INDEX IF NOT EXISTS comments_search_idx ON comments USING GIN (search);
@@ -0,0 +1,13 @@
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_user_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_user_id_fkey
+	FOREIGN KEY (user_id) REFERENCES users(id);
+
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_post_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_post_id_fkey
+	FOREIGN KEY (post_id) REFERENCES posts(id);
+
+ALTER TABLE comments ALTER COLUMN post_id DROP NOT NULL;
+
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_user_id_fkey;
+ALTER TABLE posts ADD CONSTRAINT posts_user_id_fkey
+	FOREIGN KEY (user_id) REFERENCES users(id);
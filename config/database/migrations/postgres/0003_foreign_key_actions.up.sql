@@ -0,0 +1,16 @@
+-- Deleting a post deletes its comments. Deleting a user keeps their posts and
+-- comments but anonymizes them by clearing user_id.
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_user_id_fkey;
+ALTER TABLE posts ADD CONSTRAINT posts_user_id_fkey
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+DELETE FROM comments WHERE post_id IS NULL;
+ALTER TABLE comments ALTER COLUMN post_id SET NOT NULL;
+
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_post_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_post_id_fkey
+	FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE;
+
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_user_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_user_id_fkey
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+-- Foreign keys are not indexed automatically. These cover listing a post's
+-- comments, an author's posts and comments, and the ON DELETE actions.
+CREATE INDEX IF NOT EXISTS comments_post_id_idx ON comments (post_id, created_at);
+CREATE INDEX IF NOT EXISTS comments_user_id_idx ON comments (user_id);
+CREATE INDEX IF NOT EXISTS posts_user_id_idx ON posts (user_id, created_at);
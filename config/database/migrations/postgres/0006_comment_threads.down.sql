@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS comments_parent_id_idx;
+DROP INDEX IF EXISTS comments_post_id_path_idx;
+
+-- Placeholders of deleted comments go away with their replies.
+DELETE FROM comments c USING comments d
+	WHERE d.deleted_at IS NOT NULL AND d.post_id = c.post_id AND starts_with(c.path, d.path);
+
+ALTER TABLE comments DROP COLUMN deleted_at;
+ALTER TABLE comments DROP COLUMN path;
+ALTER TABLE comments DROP COLUMN depth;
+ALTER TABLE comments DROP COLUMN parent_id;
@@ -0,0 +1,12 @@
+-- path orders a post's comments depth first: every comment appends a
+-- fixed-width "<created at>-<id>/" segment to its parent's path. The C
+-- collation keeps the punctuation significant when sorting.
+ALTER TABLE comments ADD COLUMN parent_id UUID REFERENCES comments(id) ON DELETE CASCADE;
+ALTER TABLE comments ADD COLUMN depth INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN path TEXT COLLATE "C" NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN deleted_at TIMESTAMPTZ;
+
+UPDATE comments SET path = to_char(created_at AT TIME ZONE 'UTC', 'YYYYMMDDHH24MISS') || '.000000000-' || id::text || '/';
+
+CREATE INDEX IF NOT EXISTS comments_post_id_path_idx ON comments (post_id, path);
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id);
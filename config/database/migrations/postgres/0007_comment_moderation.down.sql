@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS comments_status_idx;
+
+-- Comments that were never approved would become public, so they are removed.
+-- The ones with replies are turned into deleted placeholders instead.
+UPDATE comments SET content = '', user_id = NULL, deleted_at = now()
+	WHERE status <> 'approved' AND id IN (SELECT parent_id FROM comments WHERE parent_id IS NOT NULL);
+DELETE FROM comments WHERE status <> 'approved' AND deleted_at IS NULL;
+
+ALTER TABLE posts DROP COLUMN comments_closed;
+ALTER TABLE posts DROP COLUMN comment_policy;
+ALTER TABLE comments DROP COLUMN status;
@@ -0,0 +1,8 @@
+-- Existing comments were published immediately, so they start out approved.
+ALTER TABLE comments ADD COLUMN status TEXT NOT NULL DEFAULT 'approved';
+
+-- An empty comment_policy means the site wide COMMENT_POLICY applies.
+ALTER TABLE posts ADD COLUMN comment_policy TEXT NOT NULL DEFAULT '';
+ALTER TABLE posts ADD COLUMN comments_closed BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS comments_status_idx ON comments (status, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS spam_training;
+DROP TABLE IF EXISTS spam_tokens;
+ALTER TABLE comments DROP COLUMN spam_score;
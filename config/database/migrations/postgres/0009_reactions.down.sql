@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS comment_reaction_counts;
+DROP TABLE IF EXISTS post_reaction_counts;
+DROP TABLE IF EXISTS comment_reactions;
+DROP TABLE IF EXISTS post_reactions;
+DROP FUNCTION IF EXISTS comment_reactions_count();
+DROP FUNCTION IF EXISTS post_reactions_count();
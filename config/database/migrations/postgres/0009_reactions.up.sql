@@ -0,0 +1,67 @@
+-- A user can leave each reaction type once per post or comment.
+CREATE TABLE IF NOT EXISTS post_reactions (
+	post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (post_id, user_id, type)
+);
+
+CREATE TABLE IF NOT EXISTS comment_reactions (
+	comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (comment_id, user_id, type)
+);
+
+CREATE INDEX IF NOT EXISTS post_reactions_user_id_idx ON post_reactions (user_id);
+CREATE INDEX IF NOT EXISTS comment_reactions_user_id_idx ON comment_reactions (user_id);
+
+-- Denormalized counters so lists don't have to count reactions. Triggers keep
+-- them in step, including when reactions go away with a deleted user.
+CREATE TABLE IF NOT EXISTS post_reaction_counts (
+	post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	count INTEGER NOT NULL,
+	PRIMARY KEY (post_id, type)
+);
+
+CREATE TABLE IF NOT EXISTS comment_reaction_counts (
+	comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	count INTEGER NOT NULL,
+	PRIMARY KEY (comment_id, type)
+);
+
+CREATE OR REPLACE FUNCTION post_reactions_count() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		INSERT INTO post_reaction_counts (post_id, type, count) VALUES (NEW.post_id, NEW.type, 1)
+			ON CONFLICT (post_id, type) DO UPDATE SET count = post_reaction_counts.count + 1;
+	ELSE
+		UPDATE post_reaction_counts SET count = count - 1 WHERE post_id = OLD.post_id AND type = OLD.type;
+		DELETE FROM post_reaction_counts WHERE post_id = OLD.post_id AND type = OLD.type AND count <= 0;
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_reactions_count AFTER INSERT OR DELETE ON post_reactions
+	FOR EACH ROW EXECUTE FUNCTION post_reactions_count();
+
+CREATE OR REPLACE FUNCTION comment_reactions_count() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		INSERT INTO comment_reaction_counts (comment_id, type, count) VALUES (NEW.comment_id, NEW.type, 1)
+			ON CONFLICT (comment_id, type) DO UPDATE SET count = comment_reaction_counts.count + 1;
+	ELSE
+		UPDATE comment_reaction_counts SET count = count - 1 WHERE comment_id = OLD.comment_id AND type = OLD.type;
+		DELETE FROM comment_reaction_counts WHERE comment_id = OLD.comment_id AND type = OLD.type AND count <= 0;
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comment_reactions_count AFTER INSERT OR DELETE ON comment_reactions
+	FOR EACH ROW EXECUTE FUNCTION comment_reactions_count();
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS bookmarks (
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS bookmarks_post_id_idx ON bookmarks (post_id);
+
+CREATE TABLE IF NOT EXISTS reading_lists (
+	id UUID PRIMARY KEY,
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	is_public BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS reading_list_posts (
+	list_id UUID NOT NULL REFERENCES reading_lists(id) ON DELETE CASCADE,
+	post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+	added_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (list_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS reading_list_posts_post_id_idx ON reading_list_posts (post_id);
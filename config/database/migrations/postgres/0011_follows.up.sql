@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS follows (
+	follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (follower_id, followee_id),
+	CHECK (follower_id <> followee_id)
+);
+
+-- The primary key answers "who does this user follow"; these cover the
+-- follower and following lists, which are ordered by when the follow happened.
+CREATE INDEX IF NOT EXISTS follows_followee_idx ON follows (followee_id, created_at);
+CREATE INDEX IF NOT EXISTS follows_follower_created_idx ON follows (follower_id, created_at);
+
+-- Lets the feed walk published posts newest first and stop after one page,
+-- no matter how many authors the reader follows.
+CREATE INDEX IF NOT EXISTS posts_status_created_idx ON posts (status, created_at, id);
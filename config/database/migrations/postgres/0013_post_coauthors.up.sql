@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS post_coauthors (
+	post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (post_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS post_coauthors_user_idx ON post_coauthors (user_id);
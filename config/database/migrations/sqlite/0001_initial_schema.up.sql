@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS users (
+	id BLOB PRIMARY KEY,
+	firstName TEXT NOT NULL,
+	lastName TEXT NOT NULL,
+	username TEXT NOT NULL UNIQUE,
+	email TEXT NOT NULL UNIQUE,
+	password TEXT NOT NULL,
+	salt TEXT NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'
+);
+
+CREATE TABLE IF NOT EXISTS posts (
+	id BLOB PRIMARY KEY,
+	title TEXT NOT NULL,
+	content TEXT,
+	content_html TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'published',
+	user_id BLOB,
+	created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+	id BLOB PRIMARY KEY,
+	content TEXT,
+	post_id BLOB,
+	user_id BLOB,
+	created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	FOREIGN KEY(post_id) REFERENCES posts(id),
+	FOREIGN KEY(user_id) REFERENCES users(id)
+);
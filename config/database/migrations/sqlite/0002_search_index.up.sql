@@ -0,0 +1,45 @@
+-- The FTS tables keep their own copy of the text keyed by the entity id.
+-- posts and comments have no INTEGER PRIMARY KEY, so their rowids are not
+-- stable enough to use an external content table.
+CREATE VIRTUAL TABLE IF NOT EXISTS posts_fts USING fts5(
+	id UNINDEXED,
+	title,
+	content,
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_insert AFTER INSERT ON posts BEGIN
+	INSERT INTO posts_fts (id, title, content) VALUES (new.id, new.title, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_update AFTER UPDATE OF title, content ON posts BEGIN
+	UPDATE posts_fts SET title = new.title, content = new.content WHERE id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_delete AFTER DELETE ON posts BEGIN
+	DELETE FROM posts_fts WHERE id = old.id;
+END;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS comments_fts USING fts5(
+	id UNINDEXED,
+	content,
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS comments_fts_insert AFTER INSERT ON comments BEGIN
+	INSERT INTO comments_fts (id, content) VALUES (new.id, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_fts_update AFTER UPDATE OF content ON comments BEGIN
+	UPDATE comments_fts SET content = new.content WHERE id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_fts_delete AFTER DELETE ON comments BEGIN
+	DELETE FROM comments_fts WHERE id = old.id;
+END;
+
+INSERT INTO posts_fts (id, title, content)
+	SELECT id, title, content FROM posts WHERE id NOT IN (SELECT id FROM posts_fts);
+
+INSERT INTO comments_fts (id, content)
+	SELECT id, content FROM comments WHERE id NOT IN (SELECT id FROM comments_fts);
@@ -0,0 +1,55 @@
+CREATE TABLE posts_old (
+	id BLOB PRIMARY KEY,
+	title TEXT NOT NULL,
+	content TEXT,
+	content_html TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'published',
+	user_id BLOB,
+	created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+INSERT INTO posts_old SELECT id, title, content, content_html, status, user_id, created_at, updated_at FROM posts;
+
+CREATE TABLE comments_old (
+	id BLOB PRIMARY KEY,
+	content TEXT,
+	post_id BLOB,
+	user_id BLOB,
+	created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	FOREIGN KEY(post_id) REFERENCES posts(id),
+	FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+INSERT INTO comments_old SELECT id, content, post_id, user_id, created_at, updated_at FROM comments;
+
+DROP TABLE comments;
+DROP TABLE posts;
+ALTER TABLE posts_old RENAME TO posts;
+ALTER TABLE comments_old RENAME TO comments;
+
+CREATE TRIGGER posts_fts_insert AFTER INSERT ON posts BEGIN
+	INSERT INTO posts_fts (id, title, content) VALUES (new.id, new.title, new.content);
+END;
+
+CREATE TRIGGER posts_fts_update AFTER UPDATE OF title, content ON posts BEGIN
+	UPDATE posts_fts SET title = new.title, content = new.content WHERE id = old.id;
+END;
+
+CREATE TRIGGER posts_fts_delete AFTER DELETE ON posts BEGIN
+	DELETE FROM posts_fts WHERE id = old.id;
+END;
+
+CREATE TRIGGER comments_fts_insert AFTER INSERT ON comments BEGIN
+	INSERT INTO comments_fts (id, content) VALUES (new.id, new.content);
+END;
+
+CREATE TRIGGER comments_fts_update AFTER UPDATE OF content ON comments BEGIN
+	UPDATE comments_fts SET content = new.content WHERE id = old.id;
+END;
+
+CREATE TRIGGER comments_fts_delete AFTER DELETE ON comments BEGIN
+	DELETE FROM comments_fts WHERE id = old.id;
+END;
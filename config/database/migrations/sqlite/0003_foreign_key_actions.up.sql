@@ -0,0 +1,75 @@
+-- SQLite can't change constraints in place, so posts and comments are rebuilt
+-- with explicit ON DELETE actions. The runner disables foreign key enforcement
+-- while migrating and runs PRAGMA foreign_key_check before committing.
+--
+-- Deleting a post deletes its comments. Deleting a user keeps their posts and
+-- comments but anonymizes them by clearing user_id.
+
+CREATE TABLE posts_new (
+	id BLOB PRIMARY KEY,
+	title TEXT NOT NULL,
+	content TEXT,
+	content_html TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'published',
+	user_id BLOB,
+	created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+INSERT INTO posts_new (id, title, content, content_html, status, user_id, created_at, updated_at)
+	SELECT id, title, content, content_html, status,
+		CASE WHEN user_id IN (SELECT id FROM users) THEN user_id END,
+		created_at, updated_at
+	FROM posts;
+
+-- Comments left behind by posts deleted before enforcement are dropped.
+CREATE TABLE comments_new (
+	id BLOB PRIMARY KEY,
+	content TEXT,
+	post_id BLOB NOT NULL,
+	user_id BLOB,
+	created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	FOREIGN KEY(post_id) REFERENCES posts(id) ON DELETE CASCADE,
+	FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+INSERT INTO comments_new (id, content, post_id, user_id, created_at, updated_at)
+	SELECT id, content, post_id,
+		CASE WHEN user_id IN (SELECT id FROM users) THEN user_id END,
+		created_at, updated_at
+	FROM comments
+	WHERE post_id IN (SELECT id FROM posts);
+
+DELETE FROM comments_fts WHERE id NOT IN (SELECT id FROM comments_new);
+
+DROP TABLE comments;
+DROP TABLE posts;
+ALTER TABLE posts_new RENAME TO posts;
+ALTER TABLE comments_new RENAME TO comments;
+
+-- Dropping the old tables dropped their search triggers.
+CREATE TRIGGER posts_fts_insert AFTER INSERT ON posts BEGIN
+	INSERT INTO posts_fts (id, title, content) VALUES (new.id, new.title, new.content);
+END;
+
+CREATE TRIGGER posts_fts_update AFTER UPDATE OF title, content ON posts BEGIN
+	UPDATE posts_fts SET title = new.title, content = new.content WHERE id = old.id;
+END;
+
+CREATE TRIGGER posts_fts_delete AFTER DELETE ON posts BEGIN
+	DELETE FROM posts_fts WHERE id = old.id;
+END;
+
+CREATE TRIGGER comments_fts_insert AFTER INSERT ON comments BEGIN
+	INSERT INTO comments_fts (id, content) VALUES (new.id, new.content);
+END;
+
+CREATE TRIGGER comments_fts_update AFTER UPDATE OF content ON comments BEGIN
+	UPDATE comments_fts SET content = new.content WHERE id = old.id;
+END;
+
+CREATE TRIGGER comments_fts_delete AFTER DELETE ON comments BEGIN
+	DELETE FROM comments_fts WHERE id = old.id;
+END;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS posts_user_id_idx;
+DROP INDEX IF EXISTS comments_user_id_idx;
+DROP INDEX IF EXISTS comments_post_id_idx;
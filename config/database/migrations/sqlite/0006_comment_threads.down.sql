@@ -0,0 +1,41 @@
+-- SQLite can't drop a column that is part of a foreign key, so comments are
+-- rebuilt without the thread columns. Placeholders of deleted comments go away
+-- with their replies.
+DELETE FROM comments WHERE path IN (
+	SELECT c.path FROM comments c
+	JOIN comments d ON d.deleted_at IS NOT NULL AND d.post_id = c.post_id AND substr(c.path, 1, length(d.path)) = d.path
+);
+
+CREATE TABLE comments_new (
+	id BLOB PRIMARY KEY,
+	content TEXT,
+	post_id BLOB NOT NULL,
+	user_id BLOB,
+	created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00',
+	FOREIGN KEY(post_id) REFERENCES posts(id) ON DELETE CASCADE,
+	FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+INSERT INTO comments_new (id, content, post_id, user_id, created_at, updated_at)
+	SELECT id, content, post_id, user_id, created_at, updated_at FROM comments;
+
+DELETE FROM comments_fts WHERE id NOT IN (SELECT id FROM comments_new);
+
+DROP TABLE comments;
+ALTER TABLE comments_new RENAME TO comments;
+
+CREATE INDEX IF NOT EXISTS comments_post_id_idx ON comments (post_id, created_at);
+CREATE INDEX IF NOT EXISTS comments_user_id_idx ON comments (user_id);
+
+CREATE TRIGGER comments_fts_insert AFTER INSERT ON comments BEGIN
+	INSERT INTO comments_fts (id, content) VALUES (new.id, new.content);
+END;
+
+CREATE TRIGGER comments_fts_update AFTER UPDATE OF content ON comments BEGIN
+	UPDATE comments_fts SET content = new.content WHERE id = old.id;
+END;
+
+CREATE TRIGGER comments_fts_delete AFTER DELETE ON comments BEGIN
+	DELETE FROM comments_fts WHERE id = old.id;
+END;
@@ -0,0 +1,11 @@
+-- path orders a post's comments depth first: every comment appends a
+-- fixed-width "<created at>-<id>/" segment to its parent's path.
+ALTER TABLE comments ADD COLUMN parent_id BLOB REFERENCES comments(id) ON DELETE CASCADE;
+ALTER TABLE comments ADD COLUMN depth INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN path TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN deleted_at DATETIME;
+
+UPDATE comments SET path = strftime('%Y%m%d%H%M%S', created_at) || '.000000000-' || id || '/';
+
+CREATE INDEX IF NOT EXISTS comments_post_id_path_idx ON comments (post_id, path);
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id);
@@ -0,0 +1,18 @@
+ALTER TABLE comments ADD COLUMN spam_score REAL NOT NULL DEFAULT 0;
+
+-- Naive Bayes model: how many spam and ham comments each token appeared in.
+CREATE TABLE IF NOT EXISTS spam_tokens (
+	token TEXT PRIMARY KEY,
+	spam INTEGER NOT NULL DEFAULT 0,
+	ham INTEGER NOT NULL DEFAULT 0
+);
+
+-- The comments the model learned from, with the tokens it counted, so a
+-- comment that is moderated again can be unlearned first. Rows outlive their
+-- comment on purpose: the counts stay in the model until it is retrained.
+CREATE TABLE IF NOT EXISTS spam_training (
+	comment_id BLOB PRIMARY KEY,
+	label TEXT NOT NULL,
+	tokens TEXT NOT NULL,
+	trained_at DATETIME NOT NULL
+);
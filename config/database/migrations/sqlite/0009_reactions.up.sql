@@ -0,0 +1,55 @@
+-- A user can leave each reaction type once per post or comment.
+CREATE TABLE IF NOT EXISTS post_reactions (
+	post_id BLOB NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+	user_id BLOB NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (post_id, user_id, type)
+);
+
+CREATE TABLE IF NOT EXISTS comment_reactions (
+	comment_id BLOB NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+	user_id BLOB NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (comment_id, user_id, type)
+);
+
+CREATE INDEX IF NOT EXISTS post_reactions_user_id_idx ON post_reactions (user_id);
+CREATE INDEX IF NOT EXISTS comment_reactions_user_id_idx ON comment_reactions (user_id);
+
+-- Denormalized counters so lists don't have to count reactions. Triggers keep
+-- them in step, including when reactions go away with a deleted user.
+CREATE TABLE IF NOT EXISTS post_reaction_counts (
+	post_id BLOB NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	count INTEGER NOT NULL,
+	PRIMARY KEY (post_id, type)
+);
+
+CREATE TABLE IF NOT EXISTS comment_reaction_counts (
+	comment_id BLOB NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	count INTEGER NOT NULL,
+	PRIMARY KEY (comment_id, type)
+);
+
+CREATE TRIGGER post_reactions_count_insert AFTER INSERT ON post_reactions BEGIN
+	INSERT INTO post_reaction_counts (post_id, type, count) VALUES (new.post_id, new.type, 1)
+		ON CONFLICT (post_id, type) DO UPDATE SET count = count + 1;
+END;
+
+CREATE TRIGGER post_reactions_count_delete AFTER DELETE ON post_reactions BEGIN
+	UPDATE post_reaction_counts SET count = count - 1 WHERE post_id = old.post_id AND type = old.type;
+	DELETE FROM post_reaction_counts WHERE post_id = old.post_id AND type = old.type AND count <= 0;
+END;
+
+CREATE TRIGGER comment_reactions_count_insert AFTER INSERT ON comment_reactions BEGIN
+	INSERT INTO comment_reaction_counts (comment_id, type, count) VALUES (new.comment_id, new.type, 1)
+		ON CONFLICT (comment_id, type) DO UPDATE SET count = count + 1;
+END;
+
+CREATE TRIGGER comment_reactions_count_delete AFTER DELETE ON comment_reactions BEGIN
+	UPDATE comment_reaction_counts SET count = count - 1 WHERE comment_id = old.comment_id AND type = old.type;
+	DELETE FROM comment_reaction_counts WHERE comment_id = old.comment_id AND type = old.type AND count <= 0;
+END;
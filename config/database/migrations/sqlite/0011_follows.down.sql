@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS posts_status_created_idx;
+DROP TABLE IF EXISTS follows;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS notifications (
+	id BLOB PRIMARY KEY,
+	user_id BLOB NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	actor_id BLOB REFERENCES users(id) ON DELETE SET NULL,
+	post_id BLOB REFERENCES posts(id) ON DELETE CASCADE,
+	comment_id BLOB REFERENCES comments(id) ON DELETE CASCADE,
+	read_at DATETIME,
+	created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_idx ON notifications (user_id, created_at);
+CREATE INDEX IF NOT EXISTS notifications_unread_idx ON notifications (user_id) WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS notifications_actor_id_idx ON notifications (actor_id);
+CREATE INDEX IF NOT EXISTS notifications_post_id_idx ON notifications (post_id);
+CREATE INDEX IF NOT EXISTS notifications_comment_id_idx ON notifications (comment_id);
+
+-- Types without a row here are enabled.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+	user_id BLOB NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	enabled BOOLEAN NOT NULL,
+	PRIMARY KEY (user_id, type)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS post_coauthors (
+	post_id BLOB NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+	user_id BLOB NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (post_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS post_coauthors_user_idx ON post_coauthors (user_id);
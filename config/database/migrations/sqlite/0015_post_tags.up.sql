@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS post_tags (
+	post_id BLOB NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+	tag TEXT NOT NULL,
+	PRIMARY KEY (post_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS post_tags_tag_idx ON post_tags (tag);
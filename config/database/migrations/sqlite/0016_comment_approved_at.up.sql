@@ -0,0 +1,5 @@
+-- When a comment was first approved. It is announced as new only then, so
+-- approving it again after a rejection doesn't announce it twice.
+ALTER TABLE comments ADD COLUMN approved_at DATETIME;
+
+UPDATE comments SET approved_at = created_at WHERE status = 'approved';
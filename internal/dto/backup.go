@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+type BackupResp struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func FromBackup(backup *models.Backup) *BackupResp {
+	return &BackupResp{
+		Name:      backup.Name,
+		Size:      backup.Size,
+		CreatedAt: backup.CreatedAt,
+	}
+}
+
+func FromBackupList(backups []*models.Backup) []*BackupResp {
+	responses := make([]*BackupResp, len(backups))
+	for i, backup := range backups {
+		responses[i] = FromBackup(backup)
+	}
+	return responses
+}
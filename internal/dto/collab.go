@@ -0,0 +1,149 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+// CoAuthorResp is a user invited to edit a post.
+type CoAuthorResp struct {
+	AuthorResp
+	AddedAt time.Time `json:"addedAt"`
+}
+
+// TextOperation is an edit in ot.js form: positive numbers retain that many
+// characters, negative numbers delete them and strings are inserted. For
+// example [3, "abc", -2, 4] keeps 3 characters, inserts "abc", deletes 2 and
+// keeps the last 4.
+type TextOperation models.TextOperation
+
+// CollabReq is a message from a collaborator: an edit made on revision, or a
+// new cursor position.
+type CollabReq struct {
+	Type         string        `json:"type" enums:"operation,cursor"`
+	Revision     int           `json:"revision"`
+	Operation    TextOperation `json:"operation" swaggertype:"array,object"`
+	Position     int           `json:"position"`
+	SelectionEnd *int          `json:"selectionEnd"`
+}
+
+type CollabCursorResp struct {
+	Position     int `json:"position"`
+	SelectionEnd int `json:"selectionEnd"`
+}
+
+type CollaboratorResp struct {
+	ClientID uuid.UUID         `json:"clientId"`
+	User     AuthorResp        `json:"user"`
+	Cursor   *CollabCursorResp `json:"cursor"`
+}
+
+// CollabResp is a message to a collaborator. Which fields are set depends on
+// its type.
+type CollabResp struct {
+	Type          string              `json:"type" enums:"snapshot,operation,ack,cursor,join,leave,error"`
+	Revision      int                 `json:"revision"`
+	Title         *string             `json:"title,omitempty"`
+	Content       *string             `json:"content,omitempty"`
+	Operation     TextOperation       `json:"operation,omitempty" swaggertype:"array,object"`
+	Collaborator  *CollaboratorResp   `json:"collaborator,omitempty"`
+	Collaborators []*CollaboratorResp `json:"collaborators,omitempty"`
+	Error         string              `json:"error,omitempty"`
+}
+
+func (op TextOperation) MarshalJSON() ([]byte, error) {
+	components := make([]any, len(op))
+	for i, c := range op {
+		switch {
+		case c.Retain > 0:
+			components[i] = c.Retain
+		case c.Insert != "":
+			components[i] = c.Insert
+		default:
+			components[i] = -c.Delete
+		}
+	}
+	return json.Marshal(components)
+}
+
+func (op *TextOperation) UnmarshalJSON(data []byte) error {
+	var components []any
+	if err := json.Unmarshal(data, &components); err != nil {
+		return err
+	}
+	*op = make(TextOperation, len(components))
+	for i, component := range components {
+		switch v := component.(type) {
+		case string:
+			(*op)[i].Insert = v
+		case float64:
+			if v == 0 || v != math.Trunc(v) {
+				return errors.New("operation lengths must be non-zero integers")
+			}
+			if v > 0 {
+				(*op)[i].Retain = int(v)
+			} else {
+				(*op)[i].Delete = int(-v)
+			}
+		default:
+			return errors.New("operation components must be numbers or strings")
+		}
+	}
+	return nil
+}
+
+func (r *CollabReq) ToCursor() models.CollabCursor {
+	cursor := models.CollabCursor{Position: r.Position, SelectionEnd: r.Position}
+	if r.SelectionEnd != nil {
+		cursor.SelectionEnd = *r.SelectionEnd
+	}
+	return cursor
+}
+
+func FromCoAuthorList(coAuthors []*models.CoAuthor) []*CoAuthorResp {
+	responses := make([]*CoAuthorResp, len(coAuthors))
+	for i, coAuthor := range coAuthors {
+		responses[i] = &CoAuthorResp{
+			AuthorResp: FromAuthor(coAuthor.User),
+			AddedAt:    coAuthor.CreatedAt,
+		}
+	}
+	return responses
+}
+
+func FromCollabMessage(message *models.CollabMessage) *CollabResp {
+	resp := &CollabResp{
+		Type:      message.Type,
+		Revision:  message.Revision,
+		Operation: TextOperation(message.Operation),
+	}
+	if message.Type == models.CollabMessageSnapshot {
+		resp.Title, resp.Content = &message.Title, &message.Content
+	}
+	if message.Collaborator != nil {
+		resp.Collaborator = fromCollaborator(message.Collaborator)
+	}
+	for _, collaborator := range message.Collaborators {
+		resp.Collaborators = append(resp.Collaborators, fromCollaborator(collaborator))
+	}
+	return resp
+}
+
+func fromCollaborator(collaborator *models.Collaborator) *CollaboratorResp {
+	resp := &CollaboratorResp{
+		ClientID: collaborator.ClientID,
+		User:     FromAuthor(collaborator.User),
+	}
+	if collaborator.Cursor != nil {
+		resp.Cursor = &CollabCursorResp{
+			Position:     collaborator.Cursor.Position,
+			SelectionEnd: collaborator.Cursor.SelectionEnd,
+		}
+	}
+	return resp
+}
@@ -1,34 +1,76 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/ahmetilboga2004/go-blog/internal/models"
 	"github.com/google/uuid"
 )
 
 type CommentRequest struct {
-	Content string    `json:"content" validate:"required"`
-	PostID  uuid.UUID `json:"postId" validate:"required" format:"uuid"`
+	Content  string     `json:"content" validate:"required"`
+	PostID   uuid.UUID  `json:"postId" validate:"required" format:"uuid"`
+	ParentID *uuid.UUID `json:"parentId,omitempty" format:"uuid"`
 }
 
 type CommentResponse struct {
-	ID      uuid.UUID `json:"id"`
-	Content string    `json:"content"`
-	UserID  uuid.UUID `json:"userId"`
-	PostID  uuid.UUID `json:"postId"`
+	ID          uuid.UUID      `json:"id"`
+	Content     string         `json:"content"`
+	UserID      *uuid.UUID     `json:"userId"` // null once the author deleted the comment or their account
+	PostID      uuid.UUID      `json:"postId"`
+	ParentID    *uuid.UUID     `json:"parentId"`
+	Depth       int            `json:"depth"`
+	Deleted     bool           `json:"deleted"`
+	Status      string         `json:"status" enums:"pending,approved,rejected,spam"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	Reactions   map[string]int `json:"reactions"`
+	MyReactions []string       `json:"myReactions"`
+}
+
+// ModerationReq moves several comments to a moderation state at once.
+type ModerationReq struct {
+	IDs    []uuid.UUID `json:"ids" validate:"required,min=1,max=100"`
+	Status string      `json:"status" validate:"required,oneof=approved rejected spam pending" enums:"approved,rejected,spam,pending"`
+}
+
+// ModerationCommentResp is a comment as moderators see it, with the score
+// the spam pipeline gave it.
+type ModerationCommentResp struct {
+	CommentResponse
+	SpamScore float64 `json:"spamScore"`
+}
+
+type ModerationResp struct {
+	Updated int `json:"updated"`
 }
 
 func (r *CommentRequest) ToModel() *models.Comment {
 	return &models.Comment{
-		Content: r.Content,
+		Content:  r.Content,
+		PostID:   r.PostID,
+		ParentID: r.ParentID,
 	}
 }
 
 func CommentResponseFromModel(comment *models.Comment) *CommentResponse {
+	var userID *uuid.UUID
+	if comment.UserID != uuid.Nil {
+		userID = &comment.UserID
+	}
 	return &CommentResponse{
-		ID:      comment.ID,
-		Content: comment.Content,
-		UserID:  comment.UserID,
-		PostID:  comment.PostID,
+		ID:          comment.ID,
+		Content:     comment.Content,
+		UserID:      userID,
+		PostID:      comment.PostID,
+		ParentID:    comment.ParentID,
+		Depth:       comment.Depth,
+		Deleted:     comment.Deleted,
+		Status:      comment.Status,
+		CreatedAt:   comment.CreatedAt,
+		UpdatedAt:   comment.UpdatedAt,
+		Reactions:   reactionCounts(comment.Reactions.Counts),
+		MyReactions: myReactions(comment.Reactions.Mine),
 	}
 }
 
@@ -39,3 +81,14 @@ func CommentListResponse(comments []*models.Comment) []*CommentResponse {
 	}
 	return responses
 }
+
+func ModerationListResponse(comments []*models.Comment) []*ModerationCommentResp {
+	responses := make([]*ModerationCommentResp, len(comments))
+	for i, comment := range comments {
+		responses[i] = &ModerationCommentResp{
+			CommentResponse: *CommentResponseFromModel(comment),
+			SpamScore:       comment.SpamScore,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,228 @@
+package dto
+
+import (
+	"encoding/xml"
+	"net/url"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+// FeedInfo describes a feed whatever its format. Link is the page the feed
+// follows and FeedURL the address the feed itself is served from.
+type FeedInfo struct {
+	Title       string
+	Description string
+	Link        string
+	FeedURL     string
+	BaseURL     string
+	Updated     time.Time
+}
+
+// PostURL, AuthorURL and TagURL link to the pages of the HTML frontend.
+func PostURL(baseURL string, id uuid.UUID) string {
+	return baseURL + "/post/" + id.String()
+}
+
+func AuthorURL(baseURL string, id uuid.UUID) string {
+	return baseURL + "/author/" + id.String()
+}
+
+func TagURL(baseURL, tag string) string {
+	return baseURL + "/tag/" + url.PathEscape(tag)
+}
+
+// RSS is an RSS 2.0 document.
+type RSS struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel RSSChannel `xml:"channel"`
+}
+
+type RSSChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          AtomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []RSSItem `xml:"item"`
+}
+
+type RSSItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        string   `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Creator     string   `xml:"dc:creator"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"`
+}
+
+// AtomFeed is an Atom 1.0 document.
+type AtomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []AtomLink  `xml:"link"`
+	Entries  []AtomEntry `xml:"entry"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type AtomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       AtomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     AtomPerson     `xml:"author"`
+	Categories []AtomCategory `xml:"category"`
+	Content    AtomContent    `xml:"content"`
+}
+
+type AtomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type AtomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// JSONFeed is a JSON Feed 1.1 document.
+type JSONFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+type JSONFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	DatePublished time.Time        `json:"date_published"`
+	DateModified  time.Time        `json:"date_modified"`
+	Authors       []JSONFeedAuthor `json:"authors"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// authorURL is empty for deleted authors.
+func authorURL(baseURL string, author models.Author) string {
+	if author.ID == uuid.Nil {
+		return ""
+	}
+	return AuthorURL(baseURL, author.ID)
+}
+
+func ToRSS(info FeedInfo, posts []*models.Post) *RSS {
+	description := info.Description
+	if description == "" {
+		// RSS requires a channel description.
+		description = info.Title
+	}
+	feed := &RSS{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: RSSChannel{
+			Title:       info.Title,
+			Link:        info.Link,
+			Description: description,
+			Self:        AtomLink{Href: info.FeedURL, Rel: "self", Type: "application/rss+xml"},
+			Items:       []RSSItem{},
+		},
+	}
+	if !info.Updated.IsZero() {
+		feed.Channel.LastBuildDate = info.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, post := range posts {
+		link := PostURL(info.BaseURL, post.ID)
+		feed.Channel.Items = append(feed.Channel.Items, RSSItem{
+			Title:       post.Title,
+			Link:        link,
+			GUID:        link,
+			PubDate:     post.CreatedAt.UTC().Format(time.RFC1123Z),
+			Creator:     post.Author.DisplayName,
+			Categories:  post.Tags,
+			Description: post.ContentHTML,
+		})
+	}
+	return feed
+}
+
+func ToAtom(info FeedInfo, posts []*models.Post) *AtomFeed {
+	feed := &AtomFeed{
+		Title:    info.Title,
+		Subtitle: info.Description,
+		ID:       info.FeedURL,
+		Updated:  info.Updated.UTC().Format(time.RFC3339),
+		Links: []AtomLink{
+			{Href: info.FeedURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: info.Link, Rel: "alternate"},
+		},
+		Entries: []AtomEntry{},
+	}
+	for _, post := range posts {
+		entry := AtomEntry{
+			Title:     post.Title,
+			ID:        "urn:uuid:" + post.ID.String(),
+			Link:      AtomLink{Href: PostURL(info.BaseURL, post.ID), Rel: "alternate"},
+			Published: post.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   post.UpdatedAt.UTC().Format(time.RFC3339),
+			Author:    AtomPerson{Name: post.Author.DisplayName, URI: authorURL(info.BaseURL, post.Author)},
+			Content:   AtomContent{Type: "html", Body: post.ContentHTML},
+		}
+		for _, tag := range post.Tags {
+			entry.Categories = append(entry.Categories, AtomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
+
+func ToJSONFeed(info FeedInfo, posts []*models.Post) *JSONFeed {
+	feed := &JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       info.Title,
+		HomePageURL: info.Link,
+		FeedURL:     info.FeedURL,
+		Description: info.Description,
+		Items:       []JSONFeedItem{},
+	}
+	for _, post := range posts {
+		feed.Items = append(feed.Items, JSONFeedItem{
+			ID:            post.ID.String(),
+			URL:           PostURL(info.BaseURL, post.ID),
+			Title:         post.Title,
+			ContentHTML:   post.ContentHTML,
+			DatePublished: post.CreatedAt.UTC(),
+			DateModified:  post.UpdatedAt.UTC(),
+			Authors:       []JSONFeedAuthor{{Name: post.Author.DisplayName, URL: authorURL(info.BaseURL, post.Author)}},
+			Tags:          post.Tags,
+		})
+	}
+	return feed
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type NotificationResp struct {
+	ID        uuid.UUID  `json:"id"`
+	Type      string     `json:"type" enums:"comment,reply,follow,post"`
+	Actor     AuthorResp `json:"actor"`
+	PostID    *uuid.UUID `json:"postId"`
+	PostTitle string     `json:"postTitle,omitempty"`
+	CommentID *uuid.UUID `json:"commentId"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"readAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type UnreadCountResp struct {
+	Unread int `json:"unread"`
+}
+
+type MarkReadResp struct {
+	Updated int `json:"updated"`
+}
+
+// NotificationPreferences maps each notification type to whether it is
+// delivered. Updates may leave types out to keep them as they are.
+type NotificationPreferences map[string]bool
+
+func FromNotification(n *models.Notification) *NotificationResp {
+	return &NotificationResp{
+		ID:        n.ID,
+		Type:      n.Type,
+		Actor:     FromAuthor(n.Actor),
+		PostID:    n.PostID,
+		PostTitle: n.PostTitle,
+		CommentID: n.CommentID,
+		Read:      n.ReadAt != nil,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+func FromNotificationList(notifications []*models.Notification) []*NotificationResp {
+	resp := make([]*NotificationResp, len(notifications))
+	for i, n := range notifications {
+		resp[i] = FromNotification(n)
+	}
+	return resp
+}
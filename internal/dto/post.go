@@ -1,60 +1,132 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/ahmetilboga2004/go-blog/internal/models"
 	"github.com/google/uuid"
 )
 
 type PostReq struct {
-	Title   string `json:"title" validate:"required,min=5,max=50"`
-	Content string `json:"content" validate:"required,min=5,max=1000"`
+	Title string `json:"title" validate:"required,min=5,max=50"`
+	// Markdown source (CommonMark with GFM tables and code fences)
+	Content string `json:"content" validate:"required,min=5,max=100000"`
+	Status  string `json:"status" validate:"omitempty,oneof=draft published" enums:"draft,published"`
+	// Tags are lowercased, with spaces turned into hyphens. Leave out on
+	// update to keep the current tags.
+	Tags []string `json:"tags,omitempty" validate:"max=10,dive,min=1,max=30"`
+}
+
+// CommentSettingsReq changes how comments on a post are handled. An empty
+// policy falls back to the site wide one.
+type CommentSettingsReq struct {
+	Policy string `json:"policy" validate:"omitempty,oneof=auto first_time moderate" enums:"auto,first_time,moderate"`
+	Closed bool   `json:"closed"`
 }
 
 type PostResp struct {
-	ID      uuid.UUID `json:"id"`
-	Title   string    `json:"title"`
-	Content string    `json:"content"`
-	UserID  uuid.UUID `json:"userId"`
+	ID             uuid.UUID      `json:"id"`
+	Title          string         `json:"title"`
+	Content        string         `json:"content"`
+	ContentHTML    string         `json:"contentHtml"`
+	Status         string         `json:"status"`
+	Tags           []string       `json:"tags"`
+	UserID         uuid.UUID      `json:"userId"`
+	Author         AuthorResp     `json:"author"`
+	CommentCount   int            `json:"commentCount"`
+	CommentPolicy  string         `json:"commentPolicy"`
+	CommentsClosed bool           `json:"commentsClosed"`
+	Reactions      map[string]int `json:"reactions"`
+	MyReactions    []string       `json:"myReactions"`
+	IsBookmarked   bool           `json:"isBookmarked"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
 }
 
+// PostDetailResp embeds the first page of comments; the rest are fetched from
+// GET /posts/{id}/comments with CommentsNextCursor.
 type PostDetailResp struct {
-	ID       uuid.UUID        `json:"id"`
-	Title    string           `json:"title"`
-	Content  string           `json:"content"`
-	UserID   uuid.UUID        `json:"userId"`
-	Comments []models.Comment `json:"comments"`
+	ID                 uuid.UUID          `json:"id"`
+	Title              string             `json:"title"`
+	Content            string             `json:"content"`
+	ContentHTML        string             `json:"contentHtml"`
+	Status             string             `json:"status"`
+	Tags               []string           `json:"tags"`
+	UserID             uuid.UUID          `json:"userId"`
+	Author             AuthorResp         `json:"author"`
+	CreatedAt          time.Time          `json:"createdAt"`
+	UpdatedAt          time.Time          `json:"updatedAt"`
+	CommentCount       int                `json:"commentCount"`
+	CommentPolicy      string             `json:"commentPolicy"`
+	CommentsClosed     bool               `json:"commentsClosed"`
+	Reactions          map[string]int     `json:"reactions"`
+	MyReactions        []string           `json:"myReactions"`
+	IsBookmarked       bool               `json:"isBookmarked"`
+	Comments           []*CommentResponse `json:"comments"`
+	CommentsNextCursor string             `json:"commentsNextCursor,omitempty"`
 }
 
 func (r *PostReq) ToModel() *models.Post {
+	status := r.Status
+	if status == "" {
+		status = models.PostStatusPublished
+	}
 	return &models.Post{
 		Title:   r.Title,
 		Content: r.Content,
+		Status:  status,
+		Tags:    r.Tags,
 	}
 }
 
 func FromPost(post *models.Post) *PostResp {
 	return &PostResp{
-		ID:      post.ID,
-		Title:   post.Title,
-		Content: post.Content,
-		UserID:  post.UserID,
+		ID:             post.ID,
+		Title:          post.Title,
+		Content:        post.Content,
+		ContentHTML:    post.ContentHTML,
+		Status:         post.Status,
+		Tags:           post.Tags,
+		UserID:         post.UserID,
+		Author:         FromAuthor(post.Author),
+		CommentCount:   post.CommentCount,
+		CommentPolicy:  post.CommentPolicy,
+		CommentsClosed: post.CommentsClosed,
+		Reactions:      reactionCounts(post.Reactions.Counts),
+		MyReactions:    myReactions(post.Reactions.Mine),
+		IsBookmarked:   post.IsBookmarked,
+		CreatedAt:      post.CreatedAt,
+		UpdatedAt:      post.UpdatedAt,
 	}
 }
 
 func FromPostDetail(post *models.Post) *PostDetailResp {
 	return &PostDetailResp{
-		ID:       post.ID,
-		Title:    post.Title,
-		Content:  post.Content,
-		UserID:   post.UserID,
-		Comments: post.Comments,
+		ID:                 post.ID,
+		Title:              post.Title,
+		Content:            post.Content,
+		ContentHTML:        post.ContentHTML,
+		Status:             post.Status,
+		Tags:               post.Tags,
+		UserID:             post.UserID,
+		Author:             FromAuthor(post.Author),
+		CreatedAt:          post.CreatedAt,
+		UpdatedAt:          post.UpdatedAt,
+		CommentCount:       post.CommentCount,
+		CommentPolicy:      post.CommentPolicy,
+		CommentsClosed:     post.CommentsClosed,
+		Reactions:          reactionCounts(post.Reactions.Counts),
+		MyReactions:        myReactions(post.Reactions.Mine),
+		IsBookmarked:       post.IsBookmarked,
+		Comments:           CommentListResponse(post.Comments),
+		CommentsNextCursor: post.CommentsNext,
 	}
 }
 
-func FromPostList(posts []*models.Post) []*PostDetailResp {
-	resp := make([]*PostDetailResp, len(posts))
+func FromPostList(posts []*models.Post) []*PostResp {
+	resp := make([]*PostResp, len(posts))
 	for i, post := range posts {
-		resp[i] = FromPostDetail(post)
+		resp[i] = FromPost(post)
 	}
 	return resp
 }
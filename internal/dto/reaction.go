@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type ReactionsResp struct {
+	Reactions   map[string]int `json:"reactions"`
+	MyReactions []string       `json:"myReactions"`
+}
+
+func FromReactions(reactions *models.Reactions) *ReactionsResp {
+	return &ReactionsResp{
+		Reactions:   reactionCounts(reactions.Counts),
+		MyReactions: myReactions(reactions.Mine),
+	}
+}
+
+// reactionCounts and myReactions render missing reactions as {} and [] rather
+// than null.
+func reactionCounts(counts map[string]int) map[string]int {
+	if counts == nil {
+		return map[string]int{}
+	}
+	return counts
+}
+
+func myReactions(mine []string) []string {
+	if mine == nil {
+		return []string{}
+	}
+	return mine
+}
+
+// ReactionChangeResp carries the new reaction counts of a post or comment to
+// live subscribers of the post.
+type ReactionChangeResp struct {
+	Target    string         `json:"target" enums:"post,comment"`
+	TargetID  uuid.UUID      `json:"targetId"`
+	PostID    uuid.UUID      `json:"postId"`
+	Reactions map[string]int `json:"reactions"`
+}
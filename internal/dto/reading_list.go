@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type ReadingListReq struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	IsPublic    bool   `json:"isPublic"`
+}
+
+type ReadingListResp struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"userId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsPublic    bool      `json:"isPublic"`
+	PostCount   int       `json:"postCount"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (r *ReadingListReq) ToModel() *models.ReadingList {
+	return &models.ReadingList{
+		Name:        r.Name,
+		Description: r.Description,
+		IsPublic:    r.IsPublic,
+	}
+}
+
+func FromReadingList(list *models.ReadingList) *ReadingListResp {
+	return &ReadingListResp{
+		ID:          list.ID,
+		UserID:      list.UserID,
+		Name:        list.Name,
+		Description: list.Description,
+		IsPublic:    list.IsPublic,
+		PostCount:   list.PostCount,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+}
+
+func FromReadingLists(lists []*models.ReadingList) []*ReadingListResp {
+	resp := make([]*ReadingListResp, len(lists))
+	for i, list := range lists {
+		resp[i] = FromReadingList(list)
+	}
+	return resp
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type SearchResultResp struct {
+	Type      string    `json:"type"`
+	ID        uuid.UUID `json:"id"`
+	PostID    uuid.UUID `json:"postId"`
+	Title     string    `json:"title"`
+	Snippet   string    `json:"snippet"`
+	Rank      float64   `json:"rank"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func FromSearchResult(result *models.SearchResult) *SearchResultResp {
+	return &SearchResultResp{
+		Type:      result.Type,
+		ID:        result.ID,
+		PostID:    result.PostID,
+		Title:     result.Title,
+		Snippet:   result.Snippet,
+		Rank:      result.Rank,
+		CreatedAt: result.CreatedAt,
+	}
+}
+
+func FromSearchResultList(results []*models.SearchResult) []*SearchResultResp {
+	resp := make([]*SearchResultResp, len(results))
+	for i, result := range results {
+		resp[i] = FromSearchResult(result)
+	}
+	return resp
+}
@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type SitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type SitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []SitemapURL `xml:"sitemap"`
+}
+
+func sitemapLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func FromSitemapEntry(baseURL string, entry *models.SitemapEntry) SitemapURL {
+	var loc string
+	switch entry.Kind {
+	case models.SitemapPost:
+		loc = PostURL(baseURL, entry.ID)
+	case models.SitemapAuthor:
+		loc = AuthorURL(baseURL, entry.ID)
+	case models.SitemapTag:
+		loc = TagURL(baseURL, entry.Tag)
+	}
+	return SitemapURL{Loc: loc, LastMod: sitemapLastMod(entry.LastMod)}
+}
+
+func ToSitemapURLSet(baseURL string, entries []*models.SitemapEntry) *SitemapURLSet {
+	urlSet := &SitemapURLSet{XMLNS: sitemapNS, URLs: make([]SitemapURL, len(entries))}
+	for i, entry := range entries {
+		urlSet.URLs[i] = FromSitemapEntry(baseURL, entry)
+	}
+	return urlSet
+}
+
+// ToSitemapIndex lists the pages of a sitemap split in pages of pageSize
+// entries, each modified when its latest entry was.
+func ToSitemapIndex(pageURL func(page int) string, entries []*models.SitemapEntry, pageSize int) *SitemapIndex {
+	index := &SitemapIndex{XMLNS: sitemapNS}
+	for start := 0; start < len(entries); start += pageSize {
+		var lastMod time.Time
+		for _, entry := range entries[start:min(start+pageSize, len(entries))] {
+			if entry.LastMod.After(lastMod) {
+				lastMod = entry.LastMod
+			}
+		}
+		index.Sitemaps = append(index.Sitemaps, SitemapURL{
+			Loc:     pageURL(start/pageSize + 1),
+			LastMod: sitemapLastMod(lastMod),
+		})
+	}
+	return index
+}
@@ -0,0 +1,49 @@
+package dto
+
+import "github.com/ahmetilboga2004/go-blog/internal/models"
+
+type SpamCheckResp struct {
+	Check  string  `json:"check"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+type SpamReportResp struct {
+	Score   float64          `json:"score"`
+	Verdict string           `json:"verdict" enums:"ham,review,spam"`
+	Checks  []*SpamCheckResp `json:"checks"`
+}
+
+type SpamTokenResp struct {
+	Token string `json:"token"`
+	Spam  int    `json:"spam"`
+	Ham   int    `json:"ham"`
+}
+
+type SpamStatsResp struct {
+	SpamDocuments int              `json:"spamDocuments"`
+	HamDocuments  int              `json:"hamDocuments"`
+	Tokens        int              `json:"tokens"`
+	TopTokens     []*SpamTokenResp `json:"topTokens"`
+}
+
+func FromSpamReport(report *models.SpamReport) *SpamReportResp {
+	checks := make([]*SpamCheckResp, len(report.Results))
+	for i, result := range report.Results {
+		checks[i] = &SpamCheckResp{Check: result.Check, Score: result.Score, Reason: result.Reason}
+	}
+	return &SpamReportResp{Score: report.Score, Verdict: report.Verdict, Checks: checks}
+}
+
+func FromSpamStats(stats *models.SpamStats) *SpamStatsResp {
+	tokens := make([]*SpamTokenResp, len(stats.TopTokens))
+	for i, token := range stats.TopTokens {
+		tokens[i] = &SpamTokenResp{Token: token.Token, Spam: token.Spam, Ham: token.Ham}
+	}
+	return &SpamStatsResp{
+		SpamDocuments: stats.SpamDocuments,
+		HamDocuments:  stats.HamDocuments,
+		Tokens:        stats.Tokens,
+		TopTokens:     tokens,
+	}
+}
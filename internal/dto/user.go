@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/ahmetilboga2004/go-blog/internal/models"
 	"github.com/google/uuid"
 )
@@ -24,6 +26,30 @@ type UserResponse struct {
 	LastName  string    `json:"lastName"`
 	Username  string    `json:"username"`
 	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// UserProfileResponse is a single user with their follow counts. IsFollowing
+// tells whether the current user follows them.
+type UserProfileResponse struct {
+	UserResponse
+	FollowerCount  int  `json:"followerCount"`
+	FollowingCount int  `json:"followingCount"`
+	IsFollowing    bool `json:"isFollowing"`
+}
+
+// FollowResp is a user in a follower or following list.
+type FollowResp struct {
+	AuthorResp
+	FollowedAt time.Time `json:"followedAt"`
+}
+
+type AuthorResp struct {
+	ID          uuid.UUID `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"displayName"`
 }
 
 func (r *UserRequest) ToModel() *models.User {
@@ -43,6 +69,37 @@ func UserResponseFromModel(user *models.User) *UserResponse {
 		LastName:  user.LastName,
 		Username:  user.Username,
 		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func UserProfileResponseFromModel(user *models.User) *UserProfileResponse {
+	return &UserProfileResponse{
+		UserResponse:   *UserResponseFromModel(user),
+		FollowerCount:  user.FollowerCount,
+		FollowingCount: user.FollowingCount,
+		IsFollowing:    user.IsFollowing,
+	}
+}
+
+func FromFollowList(follows []*models.Follow) []*FollowResp {
+	responses := make([]*FollowResp, len(follows))
+	for i, follow := range follows {
+		responses[i] = &FollowResp{
+			AuthorResp: FromAuthor(follow.User),
+			FollowedAt: follow.CreatedAt,
+		}
+	}
+	return responses
+}
+
+func FromAuthor(author models.Author) AuthorResp {
+	return AuthorResp{
+		ID:          author.ID,
+		Username:    author.Username,
+		DisplayName: author.DisplayName,
 	}
 }
 
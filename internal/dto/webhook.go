@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type WebhookReq struct {
+	URL    string   `json:"url" validate:"required,url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=post.published post.updated post.deleted comment.created" enums:"post.published,post.updated,post.deleted,comment.created"`
+	// Secret signs the payloads. One is generated when left out on create,
+	// and kept as it is when left out on update.
+	Secret string `json:"secret,omitempty" validate:"omitempty,min=16,max=256"`
+	Global bool   `json:"global"`
+	Active *bool  `json:"active,omitempty"`
+}
+
+type WebhookResp struct {
+	ID        uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Global    bool      `json:"global"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WebhookCreatedResp is only returned on create, the one time the secret is
+// shown.
+type WebhookCreatedResp struct {
+	WebhookResp
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryResp struct {
+	ID             uuid.UUID  `json:"id"`
+	WebhookID      uuid.UUID  `json:"webhookId"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status" enums:"pending,succeeded,failed"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt"`
+	LastAttemptAt  *time.Time `json:"lastAttemptAt"`
+	ResponseStatus int        `json:"responseStatus,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// WebhookDeliveryDetailResp adds what was sent and what came back.
+type WebhookDeliveryDetailResp struct {
+	WebhookDeliveryResp
+	Payload      json.RawMessage `json:"payload" swaggertype:"object"`
+	ResponseBody string          `json:"responseBody,omitempty"`
+}
+
+// WebhookPayload is the body posted to webhooks.
+type WebhookPayload struct {
+	Event      string           `json:"event"`
+	OccurredAt time.Time        `json:"occurredAt"`
+	Post       *PostResp        `json:"post,omitempty"`
+	Comment    *CommentResponse `json:"comment,omitempty"`
+}
+
+func (r *WebhookReq) ToModel() *models.Webhook {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return &models.Webhook{
+		URL:    r.URL,
+		Events: r.Events,
+		Secret: r.Secret,
+		Global: r.Global,
+		Active: active,
+	}
+}
+
+func FromWebhook(webhook *models.Webhook) *WebhookResp {
+	return &WebhookResp{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		Global:    webhook.Global,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func FromWebhookList(webhooks []*models.Webhook) []*WebhookResp {
+	resp := make([]*WebhookResp, len(webhooks))
+	for i, webhook := range webhooks {
+		resp[i] = FromWebhook(webhook)
+	}
+	return resp
+}
+
+func FromWebhookDelivery(delivery *models.WebhookDelivery) *WebhookDeliveryResp {
+	resp := &WebhookDeliveryResp{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == models.DeliveryStatusPending {
+		resp.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return resp
+}
+
+func FromWebhookDeliveryDetail(delivery *models.WebhookDelivery) *WebhookDeliveryDetailResp {
+	return &WebhookDeliveryDetailResp{
+		WebhookDeliveryResp: *FromWebhookDelivery(delivery),
+		Payload:             json.RawMessage(delivery.Payload),
+		ResponseBody:        delivery.ResponseBody,
+	}
+}
+
+func FromWebhookDeliveryList(deliveries []*models.WebhookDelivery) []*WebhookDeliveryResp {
+	resp := make([]*WebhookDeliveryResp, len(deliveries))
+	for i, delivery := range deliveries {
+		resp[i] = FromWebhookDelivery(delivery)
+	}
+	return resp
+}
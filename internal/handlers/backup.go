@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+)
+
+type backupHandler struct {
+	backupService interfaces.BackupService
+}
+
+func NewBackupHandler(backupService interfaces.BackupService) *backupHandler {
+	return &backupHandler{
+		backupService: backupService,
+	}
+}
+
+// @Summary Create Backup
+// @Description Takes a consistent snapshot of the database while the server keeps running and prunes old backups. Admins only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 201 {object} dto.BackupResp
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/backups [post]
+func (h *backupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	backup, err := h.backupService.CreateBackup(r.Context())
+	if err != nil {
+		utils.HandleError(w, http.StatusInternalServerError, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusCreated, dto.FromBackup(backup))
+}
+
+// @Summary List Backups
+// @Description Lists the stored backups, newest first. Admins only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.BackupResp "Empty array if there are no backups"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/backups [get]
+func (h *backupHandler) GetAllBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := h.backupService.GetAllBackups(r.Context())
+	if err != nil {
+		utils.HandleError(w, http.StatusInternalServerError, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromBackupList(backups))
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type bookmarkHandler struct {
+	bookmarkService interfaces.BookmarkService
+}
+
+func NewBookmarkHandler(bookmarkService interfaces.BookmarkService) *bookmarkHandler {
+	return &bookmarkHandler{
+		bookmarkService: bookmarkService,
+	}
+}
+
+// GetBookmarks godoc
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Summary Get my bookmarks
+// @Description Retrieve a page of the posts the current user bookmarked
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param sort query string false "Sort field: createdAt, title (prefix with - for descending)"
+// @Success 200 {array} dto.PostResp "Empty array if no bookmarks"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /users/me/bookmarks [get]
+func (h *bookmarkHandler) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	posts, next, err := h.bookmarkService.GetBookmarks(r.Context(), userId, opts)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.SetPaginationHeaders(w, r, next)
+	utils.ResponseJSON(w, http.StatusOK, dto.FromPostList(posts))
+}
+
+// AddBookmark godoc
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Summary Bookmark a post
+// @Description Bookmarking a post twice has no effect
+// @Param postId path string true "Post ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /users/me/bookmarks/{postId} [put]
+func (h *bookmarkHandler) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := h.bookmarkService.AddBookmark(r.Context(), userId, postId); err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusNoContent, "")
+}
+
+// RemoveBookmark godoc
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Summary Remove a bookmark
+// @Param postId path string true "Post ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /users/me/bookmarks/{postId} [delete]
+func (h *bookmarkHandler) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := h.bookmarkService.RemoveBookmark(r.Context(), userId, postId); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusNoContent, "")
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+// maxCollabMessage limits the size of a message from a collaborator.
+const maxCollabMessage = 1 << 20
+
+type collabHandler struct {
+	collabService interfaces.CollabService
+}
+
+func NewCollabHandler(collabService interfaces.CollabService) *collabHandler {
+	return &collabHandler{
+		collabService: collabService,
+	}
+}
+
+// GetCoAuthors godoc
+// @Tags collaboration
+// @Accept json
+// @Produce json
+// @Summary List the co-authors of a post
+// @Description The co-authors of a draft are only shown to its author and co-authors
+// @Param id path string true "Post ID"
+// @Success 200 {array} dto.CoAuthorResp "Empty array if the post has no co-authors"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /posts/{id}/coauthors [get]
+func (h *collabHandler) GetCoAuthors(w http.ResponseWriter, r *http.Request) {
+	postId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	viewerId, _ := utils.GetUserIDFromContext(r)
+	coAuthors, err := h.collabService.GetCoAuthors(r.Context(), viewerId, postId)
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromCoAuthorList(coAuthors))
+}
+
+// AddCoAuthor godoc
+// @Tags collaboration
+// @Accept json
+// @Produce json
+// @Summary Invite a co-author
+// @Description Lets another user edit the post together with its author. Only the author can invite co-authors.
+// @Param id path string true "Post ID"
+// @Param userId path string true "User ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /posts/{id}/coauthors/{userId} [put]
+func (h *collabHandler) AddCoAuthor(w http.ResponseWriter, r *http.Request) {
+	postId, coAuthorId, ok := parseCoAuthorPath(w, r)
+	if !ok {
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := h.collabService.AddCoAuthor(r.Context(), userId, postId, coAuthorId); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusNoContent, "")
+}
+
+// RemoveCoAuthor godoc
+// @Tags collaboration
+// @Accept json
+// @Produce json
+// @Summary Remove a co-author
+// @Description The author can remove any co-author, and co-authors can remove themselves. Their open editor connections are closed.
+// @Param id path string true "Post ID"
+// @Param userId path string true "User ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /posts/{id}/coauthors/{userId} [delete]
+func (h *collabHandler) RemoveCoAuthor(w http.ResponseWriter, r *http.Request) {
+	postId, coAuthorId, ok := parseCoAuthorPath(w, r)
+	if !ok {
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := h.collabService.RemoveCoAuthor(r.Context(), userId, postId, coAuthorId); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusNoContent, "")
+}
+
+func parseCoAuthorPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	postId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	coAuthorId, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return postId, coAuthorId, true
+}
+
+// Connect godoc
+// @Tags collaboration
+// @Summary Edit a draft together
+// @Description WebSocket endpoint for editing a draft with its co-authors. Authenticate with the Authorization header or the access_token query parameter.
+// @Description The server first sends a "snapshot" with the revision, title, content and collaborators. Clients send {"type":"operation","revision":n,"operation":[...]} for edits made on revision n, in ot.js form, and {"type":"cursor","position":p,"selectionEnd":q} for cursor moves.
+// @Description The server answers edits with "ack" and relays other collaborators' "operation", "cursor", "join" and "leave" messages. After an "error" message the connection is closed; reconnect to get a fresh snapshot.
+// @Param id path string true "Post ID"
+// @Param access_token query string false "JWT, for clients that can't set headers"
+// @Success 101 {object} dto.CollabResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /posts/{id}/collab [get]
+func (h *collabHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	postId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	session, err := h.collabService.Join(r.Context(), userId, postId)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer session.Leave()
+
+	server := websocket.Server{
+		// Clients authenticate with a token rather than cookies, so requests
+		// from other origins are fine.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			ws.MaxPayloadBytes = maxCollabMessage
+			go h.write(ws, session)
+			h.read(ws, session)
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// write sends the session's messages until it ends, then closes the
+// connection.
+func (h *collabHandler) write(ws *websocket.Conn, session interfaces.CollabSession) {
+	defer ws.Close()
+	for message := range session.Messages() {
+		if err := websocket.JSON.Send(ws, dto.FromCollabMessage(message)); err != nil {
+			return
+		}
+	}
+}
+
+// read handles the client's messages until the connection closes or a
+// message can't be applied.
+func (h *collabHandler) read(ws *websocket.Conn, session interfaces.CollabSession) {
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(ws, &data); err != nil {
+			return
+		}
+		var req dto.CollabReq
+		if err := json.Unmarshal(data, &req); err != nil {
+			h.fail(ws, err)
+			return
+		}
+
+		switch req.Type {
+		case models.CollabMessageOperation:
+			if err := session.Apply(req.Revision, models.TextOperation(req.Operation)); err != nil {
+				h.fail(ws, err)
+				return
+			}
+		case models.CollabMessageCursor:
+			session.MoveCursor(req.ToCursor())
+		default:
+			h.fail(ws, errors.New("unknown message type"))
+			return
+		}
+	}
+}
+
+// fail tells the client why its connection is about to close.
+func (h *collabHandler) fail(ws *websocket.Conn, err error) {
+	websocket.JSON.Send(ws, &dto.CollabResp{Type: "error", Error: err.Error()})
+}
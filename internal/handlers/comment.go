@@ -28,7 +28,7 @@ func NewCommentHandler(commentService interfaces.CommentService) *commentHandler
 // @Accept json
 // @Produce json
 // @Summary Create a new comment
-// @Description Create a new comment
+// @Description Create a new comment, or a reply when parentId is set. Depending on the moderation policy the comment is approved right away or starts out pending
 // @Param comment body dto.CommentRequest true "Yorum bilgileri"
 // @Success 201 {object} dto.CommentResponse
 // @Success 401 {object} utils.ErrorResponse
@@ -51,7 +51,7 @@ func (h *commentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	comment := commentReq.ToModel()
-	createdComment, err := h.commentService.CreateComment(userId, comment)
+	createdComment, err := h.commentService.CreateComment(r.Context(), userId, comment)
 	if err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
@@ -65,7 +65,7 @@ func (h *commentHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @summary Get a comment by id
-// @Description Retrieve a comment by its unique ID
+// @Description Retrieve a comment by its unique ID. Comments awaiting moderation are only visible to their author, and comments on drafts only to the post author
 // @Param id path string true "Comment ID"
 // @Success 200 {object} dto.CommentResponse
 // @Failure 400 {object} utils.ErrorResponse
@@ -78,12 +78,13 @@ func (h *commentHandler) GetCommentByID(w http.ResponseWriter, r *http.Request)
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
 	}
-	comment, err := h.commentService.GetCommentByID(id)
+	viewerId, _ := utils.GetUserIDFromContext(r)
+	comment, err := h.commentService.GetCommentByID(r.Context(), viewerId, id)
 	if err != nil {
 		utils.HandleError(w, http.StatusNotFound, err)
 		return
 	}
-	utils.ResponseJSON(w, http.StatusOK, comment)
+	utils.ResponseJSON(w, http.StatusOK, dto.CommentResponseFromModel(comment))
 }
 
 // GetAllComments godoc
@@ -91,20 +92,76 @@ func (h *commentHandler) GetCommentByID(w http.ResponseWriter, r *http.Request)
 // @Accept json
 // @Produce json
 // @Summary Get all comments
-// @Description Retrieve a list of all comments
+// @Description Retrieve a page of comments using cursor based pagination
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param sort query string false "Sort field: createdAt (prefix with - for descending)"
+// @Param author query string false "Author ID"
+// @Param post query string false "Post ID"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD)"
 // @Success 200 {array} dto.CommentResponse "Empty array if no comments"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
 // @Failure 400 {object} utils.ErrorResponse
 // @Router /comments [get]
 func (h *commentHandler) GetAllComments(w http.ResponseWriter, r *http.Request) {
-	comments, err := h.commentService.GetAllComments()
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts.ViewerID, _ = utils.GetUserIDFromContext(r)
+	comments, next, err := h.commentService.GetAllComments(r.Context(), opts)
 	if err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
 	}
 	commentsRes := dto.CommentListResponse(comments)
+	utils.SetPaginationHeaders(w, r, next)
 	utils.ResponseJSON(w, http.StatusOK, commentsRes)
 }
 
+// GetPostComments godoc
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Summary Get the comments of a post
+// @Description Retrieve a page of a post's comments in thread order: every comment is followed by its replies, oldest first. depth tells how deeply a reply is nested
+// @Param id path string true "Post ID"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page or commentsNextCursor of the post"
+// @Param sort query string false "Sort field: thread, createdAt, updatedAt (prefix with - for descending)"
+// @Success 200 {array} dto.CommentResponse "Empty array if no comments"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /posts/{id}/comments [get]
+func (h *commentHandler) GetPostComments(w http.ResponseWriter, r *http.Request) {
+	postId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	if opts.Sort == "" {
+		opts.Sort = "thread"
+	}
+	viewerId, _ := utils.GetUserIDFromContext(r)
+	comments, next, err := h.commentService.GetPostComments(r.Context(), viewerId, postId, opts)
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.SetPaginationHeaders(w, r, next)
+	utils.ResponseJSON(w, http.StatusOK, dto.CommentListResponse(comments))
+}
+
 // UpdateComment godoc
 // @Tags comments
 // @Accept json
@@ -140,7 +197,7 @@ func (h *commentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
 	}
 	comment := commentReq.ToModel()
 
-	updatedComment, err := h.commentService.UpdateComment(userId, commentId, comment)
+	updatedComment, err := h.commentService.UpdateComment(r.Context(), userId, commentId, comment)
 	if err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
@@ -174,7 +231,7 @@ func (h *commentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 		utils.HandleError(w, http.StatusUnauthorized, err)
 		return
 	}
-	if err := h.commentService.DeleteComment(userId, id); err != nil {
+	if err := h.commentService.DeleteComment(r.Context(), userId, id); err != nil {
 		utils.HandleError(w, http.StatusNotFound, err)
 		return
 	}
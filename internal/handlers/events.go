@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type eventStreamHandler struct {
+	hub         interfaces.RealtimeHub
+	postService interfaces.PostService
+	heartbeat   time.Duration
+}
+
+// NewEventStreamHandler streams realtime messages from hub to clients.
+// Subscribe its Broadcast method to the event bus to feed the hub.
+func NewEventStreamHandler(hub interfaces.RealtimeHub, postService interfaces.PostService, heartbeat time.Duration) *eventStreamHandler {
+	return &eventStreamHandler{
+		hub:         hub,
+		postService: postService,
+		heartbeat:   heartbeat,
+	}
+}
+
+// Stream godoc
+// @Tags events
+// @Produce text/event-stream
+// @Summary Stream live updates
+// @Description Server-Sent Events stream. With post, sends "comment" and "reaction" events for that post. Logged in users also get their "notification" events. A "reset" event means the client fell behind and should reload before reconnecting.
+// @Description Authenticate with the Authorization header, the access_token query parameter or the session cookie, since EventSource can't set headers.
+// @Param post query string false "Post ID"
+// @Param access_token query string false "JWT, for clients that can't set headers"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /events [get]
+func (h *eventStreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		utils.HandleError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	var topics []string
+	userId, _ := utils.GetUserIDFromContext(r)
+	if postIdStr := r.URL.Query().Get("post"); postIdStr != "" {
+		postId, err := uuid.Parse(postIdStr)
+		if err != nil {
+			utils.HandleError(w, http.StatusBadRequest, err)
+			return
+		}
+		if _, err := h.postService.GetPostByID(r.Context(), userId, postId); err != nil {
+			utils.HandleError(w, http.StatusNotFound, err)
+			return
+		}
+		topics = append(topics, models.PostTopic(postId))
+	}
+	if userId != uuid.Nil {
+		topics = append(topics, models.UserTopic(userId))
+	}
+	if len(topics) == 0 {
+		utils.HandleError(w, http.StatusBadRequest, errors.New("post is required when not logged in"))
+		return
+	}
+
+	messages, unsubscribe := h.hub.Subscribe(topics...)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				// The hub dropped us for falling behind.
+				fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+				flusher.Flush()
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", message.Event, message.Data)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// Broadcast turns domain events into realtime messages for the subscribers
+// of the post or user they concern.
+func (h *eventStreamHandler) Broadcast(ctx context.Context, event *models.Event) error {
+	var topic, name string
+	var data any
+	switch event.Type {
+	case models.EventCommentCreated:
+		topic, name = models.PostTopic(event.Post.ID), "comment"
+		data = dto.CommentResponseFromModel(event.Comment)
+	case models.EventReactionChanged:
+		change := &dto.ReactionChangeResp{
+			Target:    models.ReactionTargetPost,
+			TargetID:  event.Post.ID,
+			PostID:    event.Post.ID,
+			Reactions: event.Reactions.Counts,
+		}
+		if event.Comment != nil {
+			change.Target, change.TargetID = models.ReactionTargetComment, event.Comment.ID
+		}
+		if change.Reactions == nil {
+			change.Reactions = map[string]int{}
+		}
+		topic, name, data = models.PostTopic(event.Post.ID), "reaction", change
+	case models.EventNotificationCreated:
+		topic, name = models.UserTopic(event.UserID), "notification"
+		data = dto.FromNotification(event.Notification)
+	default:
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return h.hub.Publish(ctx, &models.RealtimeMessage{Topic: topic, Event: name, Data: payload})
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type feedHandler struct {
+	postService interfaces.PostService
+	userService interfaces.UserService
+	baseURL     string
+	title       string
+	description string
+	size        int
+}
+
+// NewFeedHandler serves the latest size published posts as RSS, Atom and
+// JSON Feed, with links built from baseURL.
+func NewFeedHandler(postService interfaces.PostService, userService interfaces.UserService, baseURL, title, description string, size int) *feedHandler {
+	return &feedHandler{
+		postService: postService,
+		userService: userService,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		title:       title,
+		description: description,
+		size:        size,
+	}
+}
+
+// SiteFeed godoc
+// @Tags feeds
+// @Produce xml
+// @Produce json
+// @Summary Get the feed of the blog
+// @Description The latest published posts as RSS 2.0 (feed.xml), Atom (atom.xml) or JSON Feed (feed.json). Supports conditional GET with ETag and Last-Modified.
+// @Success 200 {string} string "Feed"
+// @Success 304
+// @Router /feed.xml [get]
+// @Router /atom.xml [get]
+// @Router /feed.json [get]
+func (h *feedHandler) SiteFeed(w http.ResponseWriter, r *http.Request) {
+	info := dto.FeedInfo{
+		Title:       h.title,
+		Description: h.description,
+		Link:        h.baseURL + "/",
+	}
+	h.serve(w, r, "", info, models.ListOptions{})
+}
+
+// AuthorFeed godoc
+// @Tags feeds
+// @Produce xml
+// @Produce json
+// @Summary Get the feed of an author
+// @Description The author's latest published posts as RSS 2.0 (feed.xml), Atom (atom.xml) or JSON Feed (feed.json). Supports conditional GET with ETag and Last-Modified.
+// @Param id path string true "User ID"
+// @Success 200 {string} string "Feed"
+// @Success 304
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /users/{id}/feed.xml [get]
+// @Router /users/{id}/atom.xml [get]
+// @Router /users/{id}/feed.json [get]
+func (h *feedHandler) AuthorFeed(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	user, err := h.userService.GetUserByID(r.Context(), userId)
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	info := dto.FeedInfo{
+		Title:       name + " - " + h.title,
+		Description: "Posts by " + name,
+		Link:        dto.AuthorURL(h.baseURL, userId),
+	}
+	h.serve(w, r, "/users/"+userId.String(), info, models.ListOptions{AuthorID: userId})
+}
+
+// TagFeed godoc
+// @Tags feeds
+// @Produce xml
+// @Produce json
+// @Summary Get the feed of a tag
+// @Description The latest published posts with the tag as RSS 2.0 (feed.xml), Atom (atom.xml) or JSON Feed (feed.json). Supports conditional GET with ETag and Last-Modified.
+// @Param tag path string true "Tag"
+// @Success 200 {string} string "Feed"
+// @Success 304
+// @Failure 400 {object} utils.ErrorResponse
+// @Router /tags/{tag}/feed.xml [get]
+// @Router /tags/{tag}/atom.xml [get]
+// @Router /tags/{tag}/feed.json [get]
+func (h *feedHandler) TagFeed(w http.ResponseWriter, r *http.Request) {
+	tag := models.NormalizeTag(r.PathValue("tag"))
+	if tag == "" {
+		utils.HandleError(w, http.StatusBadRequest, errors.New("tag is required"))
+		return
+	}
+	info := dto.FeedInfo{
+		Title:       "#" + tag + " - " + h.title,
+		Description: "Posts tagged " + tag,
+		Link:        dto.TagURL(h.baseURL, tag),
+	}
+	h.serve(w, r, "/tags/"+url.PathEscape(tag), info, models.ListOptions{Tag: tag})
+}
+
+// serve renders the posts matching opts in the format named by the last
+// path segment, which is served under prefix. The ETag is a hash of the
+// rendered feed, so it also changes when a post is deleted or an author
+// renamed; Last-Modified only follows the posts' update times.
+func (h *feedHandler) serve(w http.ResponseWriter, r *http.Request, prefix string, info dto.FeedInfo, opts models.ListOptions) {
+	opts.Limit = h.size
+	opts.Sort = "createdAt"
+	opts.Desc = true
+	posts, _, err := h.postService.ListPosts(r.Context(), opts)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	info.BaseURL = h.baseURL
+	name := path.Base(r.URL.Path)
+	info.FeedURL = h.baseURL + prefix + "/" + name
+	info.Updated = time.Unix(0, 0).UTC()
+	for _, post := range posts {
+		if post.UpdatedAt.After(info.Updated) {
+			info.Updated = post.UpdatedAt
+		}
+	}
+
+	var body []byte
+	var contentType string
+	switch name {
+	case "atom.xml":
+		contentType = "application/atom+xml; charset=utf-8"
+		body, err = xml.MarshalIndent(dto.ToAtom(info, posts), "", "  ")
+		body = append([]byte(xml.Header), body...)
+	case "feed.json":
+		contentType = "application/feed+json; charset=utf-8"
+		body, err = json.MarshalIndent(dto.ToJSONFeed(info, posts), "", "  ")
+	default:
+		contentType = "application/rss+xml; charset=utf-8"
+		body, err = xml.MarshalIndent(dto.ToRSS(info, posts), "", "  ")
+		body = append([]byte(xml.Header), body...)
+	}
+	if err != nil {
+		utils.HandleError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.ServeConditional(w, r, contentType, body, info.Updated)
+}
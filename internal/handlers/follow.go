@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type followHandler struct {
+	followService interfaces.FollowService
+}
+
+func NewFollowHandler(followService interfaces.FollowService) *followHandler {
+	return &followHandler{
+		followService: followService,
+	}
+}
+
+// Follow godoc
+// @Tags follows
+// @Accept json
+// @Produce json
+// @Summary Follow a user
+// @Description Following a user twice has no effect
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /users/{id}/follow [post]
+func (h *followHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	h.follow(w, r, true)
+}
+
+// Unfollow godoc
+// @Tags follows
+// @Accept json
+// @Produce json
+// @Summary Unfollow a user
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /users/{id}/follow [delete]
+func (h *followHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	h.follow(w, r, false)
+}
+
+func (h *followHandler) follow(w http.ResponseWriter, r *http.Request, follow bool) {
+	followeeId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	if follow {
+		err = h.followService.Follow(r.Context(), userId, followeeId)
+	} else {
+		err = h.followService.Unfollow(r.Context(), userId, followeeId)
+	}
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusNoContent, "")
+}
+
+// GetFollowers godoc
+// @Tags follows
+// @Accept json
+// @Produce json
+// @Summary Get a user's followers
+// @Description Retrieve a page of the users following a user, most recent first
+// @Param id path string true "User ID"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {array} dto.FollowResp "Empty array if no followers"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} utils.ErrorResponse
+// @Router /users/{id}/followers [get]
+func (h *followHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.followService.GetFollowers)
+}
+
+// GetFollowing godoc
+// @Tags follows
+// @Accept json
+// @Produce json
+// @Summary Get the users a user follows
+// @Description Retrieve a page of the users a user follows, most recent first
+// @Param id path string true "User ID"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {array} dto.FollowResp "Empty array if not following anyone"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} utils.ErrorResponse
+// @Router /users/{id}/following [get]
+func (h *followHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.followService.GetFollowing)
+}
+
+type followLister func(ctx context.Context, userId uuid.UUID, opts models.ListOptions) ([]*models.Follow, string, error)
+
+func (h *followHandler) list(w http.ResponseWriter, r *http.Request, lister followLister) {
+	userId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	follows, next, err := lister(r.Context(), userId, opts)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.SetPaginationHeaders(w, r, next)
+	utils.ResponseJSON(w, http.StatusOK, dto.FromFollowList(follows))
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/go-playground/validator/v10"
+)
+
+type moderationHandler struct {
+	commentService interfaces.CommentService
+	validator      *validator.Validate
+}
+
+func NewModerationHandler(commentService interfaces.CommentService) *moderationHandler {
+	return &moderationHandler{
+		commentService: commentService,
+		validator:      validator.New(),
+	}
+}
+
+// GetQueue godoc
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Summary Get the moderation queue
+// @Description Retrieve a page of comments in a moderation state, oldest first. Moderators and admins only
+// @Param status query string false "Moderation state (default pending)" Enums(pending, approved, rejected, spam)
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {array} dto.ModerationCommentResp "Empty array if the queue is empty"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /moderation/comments [get]
+func (h *moderationHandler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	comments, next, err := h.commentService.GetModerationQueue(r.Context(), r.URL.Query().Get("status"), opts)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.SetPaginationHeaders(w, r, next)
+	utils.ResponseJSON(w, http.StatusOK, dto.ModerationListResponse(comments))
+}
+
+// Moderate godoc
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Summary Approve or reject comments
+// @Description Move up to 100 comments to a moderation state at once. Marking comments as spam or approving them trains the spam classifier. Moderators and admins only
+// @Param moderation body dto.ModerationReq true "Yorumlar ve yeni durum"
+// @Success 200 {object} dto.ModerationResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /moderation/comments [post]
+func (h *moderationHandler) Moderate(w http.ResponseWriter, r *http.Request) {
+	var moderationReq dto.ModerationReq
+	if err := json.NewDecoder(r.Body).Decode(&moderationReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.validator.Struct(&moderationReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	updated, err := h.commentService.ModerateComments(r.Context(), moderationReq.IDs, moderationReq.Status)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.ModerationResp{Updated: updated})
+}
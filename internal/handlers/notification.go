@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type notificationHandler struct {
+	notificationService interfaces.NotificationService
+}
+
+func NewNotificationHandler(notificationService interfaces.NotificationService) *notificationHandler {
+	return &notificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications godoc
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Summary Get my notifications
+// @Description Retrieve a page of the current user's notifications, newest first
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {array} dto.NotificationResp "Empty array if no notifications"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Header 200 {int} X-Unread-Count "Number of unread notifications"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /notifications [get]
+func (h *notificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	var unreadOnly bool
+	if unread := r.URL.Query().Get("unread"); unread != "" {
+		if unreadOnly, err = strconv.ParseBool(unread); err != nil {
+			utils.HandleError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	notifications, next, err := h.notificationService.GetNotifications(r.Context(), userId, unreadOnly, opts)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	unread, err := h.notificationService.CountUnread(r.Context(), userId)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	w.Header().Set("X-Unread-Count", strconv.Itoa(unread))
+	utils.SetPaginationHeaders(w, r, next)
+	utils.ResponseJSON(w, http.StatusOK, dto.FromNotificationList(notifications))
+}
+
+// CountUnread godoc
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Summary Count my unread notifications
+// @Success 200 {object} dto.UnreadCountResp
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /notifications/unread-count [get]
+func (h *notificationHandler) CountUnread(w http.ResponseWriter, r *http.Request) {
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	unread, err := h.notificationService.CountUnread(r.Context(), userId)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.UnreadCountResp{Unread: unread})
+}
+
+// MarkRead godoc
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Summary Mark a notification as read
+// @Param id path string true "Notification ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /notifications/{id}/read [post]
+func (h *notificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := h.notificationService.MarkRead(r.Context(), userId, id); err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusNoContent, "")
+}
+
+// MarkAllRead godoc
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Summary Mark all my notifications as read
+// @Success 200 {object} dto.MarkReadResp
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /notifications/read-all [post]
+func (h *notificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	updated, err := h.notificationService.MarkAllRead(r.Context(), userId)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.MarkReadResp{Updated: updated})
+}
+
+// GetPreferences godoc
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Summary Get my notification preferences
+// @Description Every notification type (comment, reply, follow, post) and whether it is delivered
+// @Success 200 {object} dto.NotificationPreferences
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /notifications/preferences [get]
+func (h *notificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	preferences, err := h.notificationService.GetPreferences(r.Context(), userId)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.NotificationPreferences(preferences))
+}
+
+// UpdatePreferences godoc
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Summary Update my notification preferences
+// @Description Turn notification types on or off. Types left out keep their setting
+// @Param preferences body dto.NotificationPreferences true "Types to change, e.g. {\"post\": false}"
+// @Success 200 {object} dto.NotificationPreferences
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /notifications/preferences [put]
+func (h *notificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var preferencesReq dto.NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&preferencesReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	preferences, err := h.notificationService.UpdatePreferences(r.Context(), userId, preferencesReq)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.NotificationPreferences(preferences))
+}
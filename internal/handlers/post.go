@@ -53,7 +53,7 @@ func (h *postHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	post := postReq.ToModel()
-	createdPost, err := h.postService.CreatePost(userId, post)
+	createdPost, err := h.postService.CreatePost(r.Context(), userId, post)
 	if err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
@@ -80,12 +80,13 @@ func (h *postHandler) GetPostByID(w http.ResponseWriter, r *http.Request) {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
 	}
-	post, err := h.postService.GetPostByID(id)
+	viewerId, _ := utils.GetUserIDFromContext(r)
+	post, err := h.postService.GetPostByID(r.Context(), viewerId, id)
 	if err != nil {
 		utils.HandleError(w, http.StatusNotFound, err)
 		return
 	}
-	utils.ResponseJSON(w, http.StatusOK, post)
+	utils.ResponseJSON(w, http.StatusOK, dto.FromPostDetail(post))
 }
 
 // GetAllPosts godoc
@@ -93,20 +94,70 @@ func (h *postHandler) GetPostByID(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Summary Get all posts
-// @Description Retrieve a list of all posts
+// @Description Retrieve a page of posts using cursor based pagination
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param sort query string false "Sort field: createdAt, title (prefix with - for descending)"
+// @Param author query string false "Author ID"
+// @Param tag query string false "Tag"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD)"
 // @Success 200 {array} dto.PostResp "Empty array if no posts"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
 // @Failure 400 {object} utils.ErrorResponse
 // @Router /posts [get]
 func (h *postHandler) GetAllPosts(w http.ResponseWriter, r *http.Request) {
-	posts, err := h.postService.GetAllPosts()
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts.ViewerID, _ = utils.GetUserIDFromContext(r)
+	posts, next, err := h.postService.GetAllPosts(r.Context(), opts)
 	if err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
 	}
 	postsRes := dto.FromPostList(posts)
+	utils.SetPaginationHeaders(w, r, next)
 	utils.ResponseJSON(w, http.StatusOK, postsRes)
 }
 
+// GetFeed godoc
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Summary Get my feed
+// @Description Retrieve a page of published posts from the authors the current user follows, newest first
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {array} dto.PostResp "Empty array if no posts"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /feed [get]
+func (h *postHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	posts, next, err := h.postService.GetFeed(r.Context(), userId, opts)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.SetPaginationHeaders(w, r, next)
+	utils.ResponseJSON(w, http.StatusOK, dto.FromPostList(posts))
+}
+
 // UpdatePost godoc
 // @Tags posts
 // @Accept json
@@ -146,7 +197,7 @@ func (h *postHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 
 	post := postReq.ToModel()
 
-	updatedPost, err := h.postService.UpdatePost(userId, postId, post)
+	updatedPost, err := h.postService.UpdatePost(r.Context(), userId, postId, post)
 	if err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
@@ -157,6 +208,48 @@ func (h *postHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 	utils.ResponseJSON(w, http.StatusOK, resUpdatedPost)
 }
 
+// UpdateCommentSettings godoc
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Summary Update the comment settings of a post
+// @Description Close or reopen comments on a post and choose how new comments are moderated. Only the author of the post can change them
+// @Param id path string true "Post ID"
+// @Param settings body dto.CommentSettingsReq true "Yorum ayarları"
+// @Success 200 {object} dto.PostResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /posts/{id}/comment-settings [put]
+func (h *postHandler) UpdateCommentSettings(w http.ResponseWriter, r *http.Request) {
+	postId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	var settingsReq dto.CommentSettingsReq
+	if err := json.NewDecoder(r.Body).Decode(&settingsReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.validator.Struct(&settingsReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	post, err := h.postService.UpdateCommentSettings(r.Context(), userId, postId, settingsReq.Policy, settingsReq.Closed)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromPost(post))
+}
+
 // DeletePost godoc
 // @Tags posts
 // @Accept json
@@ -181,7 +274,7 @@ func (h *postHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
 		utils.HandleError(w, http.StatusUnauthorized, err)
 		return
 	}
-	if err := h.postService.DeletePost(userId, id); err != nil {
+	if err := h.postService.DeletePost(r.Context(), userId, id); err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
 	}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type reactionHandler struct {
+	reactionService interfaces.ReactionService
+}
+
+func NewReactionHandler(reactionService interfaces.ReactionService) *reactionHandler {
+	return &reactionHandler{
+		reactionService: reactionService,
+	}
+}
+
+// ReactToPost godoc
+// @Tags reactions
+// @Accept json
+// @Produce json
+// @Summary React to a post
+// @Description Leave a like or another configured reaction on a post. Reacting twice with the same type has no effect
+// @Param id path string true "Post ID"
+// @Param type path string true "Reaction type, e.g. like"
+// @Success 200 {object} dto.ReactionsResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /posts/{id}/reactions/{type} [put]
+func (h *reactionHandler) ReactToPost(w http.ResponseWriter, r *http.Request) {
+	h.react(w, r, models.ReactionTargetPost, true)
+}
+
+// UnreactToPost godoc
+// @Tags reactions
+// @Accept json
+// @Produce json
+// @Summary Remove a reaction from a post
+// @Param id path string true "Post ID"
+// @Param type path string true "Reaction type, e.g. like"
+// @Success 200 {object} dto.ReactionsResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /posts/{id}/reactions/{type} [delete]
+func (h *reactionHandler) UnreactToPost(w http.ResponseWriter, r *http.Request) {
+	h.react(w, r, models.ReactionTargetPost, false)
+}
+
+// ReactToComment godoc
+// @Tags reactions
+// @Accept json
+// @Produce json
+// @Summary React to a comment
+// @Description Leave a like or another configured reaction on a comment. Reacting twice with the same type has no effect
+// @Param id path string true "Comment ID"
+// @Param type path string true "Reaction type, e.g. like"
+// @Success 200 {object} dto.ReactionsResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /comments/{id}/reactions/{type} [put]
+func (h *reactionHandler) ReactToComment(w http.ResponseWriter, r *http.Request) {
+	h.react(w, r, models.ReactionTargetComment, true)
+}
+
+// UnreactToComment godoc
+// @Tags reactions
+// @Accept json
+// @Produce json
+// @Summary Remove a reaction from a comment
+// @Param id path string true "Comment ID"
+// @Param type path string true "Reaction type, e.g. like"
+// @Success 200 {object} dto.ReactionsResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /comments/{id}/reactions/{type} [delete]
+func (h *reactionHandler) UnreactToComment(w http.ResponseWriter, r *http.Request) {
+	h.react(w, r, models.ReactionTargetComment, false)
+}
+
+func (h *reactionHandler) react(w http.ResponseWriter, r *http.Request, target string, add bool) {
+	targetId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	reactionType := r.PathValue("type")
+	var reactions *models.Reactions
+	if add {
+		reactions, err = h.reactionService.React(r.Context(), userId, target, targetId, reactionType)
+	} else {
+		reactions, err = h.reactionService.Unreact(r.Context(), userId, target, targetId, reactionType)
+	}
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromReactions(reactions))
+}
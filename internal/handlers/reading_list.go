@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type readingListHandler struct {
+	listService interfaces.ReadingListService
+	validator   *validator.Validate
+}
+
+func NewReadingListHandler(listService interfaces.ReadingListService) *readingListHandler {
+	return &readingListHandler{
+		listService: listService,
+		validator:   validator.New(),
+	}
+}
+
+// GetMyLists godoc
+// @Tags reading lists
+// @Accept json
+// @Produce json
+// @Summary Get my reading lists
+// @Description Retrieve all reading lists of the current user, private ones included
+// @Success 200 {array} dto.ReadingListResp "Empty array if no lists"
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /users/me/lists [get]
+func (h *readingListHandler) GetMyLists(w http.ResponseWriter, r *http.Request) {
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	lists, err := h.listService.GetUserLists(r.Context(), userId, userId)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromReadingLists(lists))
+}
+
+// GetUserLists godoc
+// @Tags reading lists
+// @Accept json
+// @Produce json
+// @Summary Get a user's public reading lists
+// @Param id path string true "User ID"
+// @Success 200 {array} dto.ReadingListResp "Empty array if no lists"
+// @Failure 400 {object} utils.ErrorResponse
+// @Router /users/{id}/lists [get]
+func (h *readingListHandler) GetUserLists(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	viewerId, _ := utils.GetUserIDFromContext(r)
+	lists, err := h.listService.GetUserLists(r.Context(), viewerId, userId)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromReadingLists(lists))
+}
+
+// Create godoc
+// @Tags reading lists
+// @Accept json
+// @Produce json
+// @Summary Create a reading list
+// @Description List names are unique per user. Lists are private unless isPublic is set
+// @Param list body dto.ReadingListReq true "Reading list"
+// @Success 201 {object} dto.ReadingListResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /users/me/lists [post]
+func (h *readingListHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var listReq dto.ReadingListReq
+	if err := json.NewDecoder(r.Body).Decode(&listReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.validator.Struct(&listReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	list, err := h.listService.CreateList(r.Context(), userId, listReq.ToModel())
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusCreated, dto.FromReadingList(list))
+}
+
+// GetList godoc
+// @Tags reading lists
+// @Accept json
+// @Produce json
+// @Summary Get a reading list
+// @Description Private lists are only visible to their owner
+// @Param id path string true "Reading list ID"
+// @Success 200 {object} dto.ReadingListResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /lists/{id} [get]
+func (h *readingListHandler) GetList(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	viewerId, _ := utils.GetUserIDFromContext(r)
+	list, err := h.listService.GetList(r.Context(), viewerId, id)
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromReadingList(list))
+}
+
+// GetListPosts godoc
+// @Tags reading lists
+// @Accept json
+// @Produce json
+// @Summary Get the posts on a reading list
+// @Description Retrieve a page of the posts on a list using cursor based pagination
+// @Param id path string true "Reading list ID"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param sort query string false "Sort field: createdAt, title (prefix with - for descending)"
+// @Success 200 {array} dto.PostResp "Empty array if the list is empty"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /lists/{id}/posts [get]
+func (h *readingListHandler) GetListPosts(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	viewerId, _ := utils.GetUserIDFromContext(r)
+	posts, next, err := h.listService.GetListPosts(r.Context(), viewerId, id, opts)
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.SetPaginationHeaders(w, r, next)
+	utils.ResponseJSON(w, http.StatusOK, dto.FromPostList(posts))
+}
+
+// UpdateList godoc
+// @Tags reading lists
+// @Accept json
+// @Produce json
+// @Summary Update a reading list
+// @Param id path string true "Reading list ID"
+// @Param list body dto.ReadingListReq true "Reading list"
+// @Success 200 {object} dto.ReadingListResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /users/me/lists/{id} [put]
+func (h *readingListHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	var listReq dto.ReadingListReq
+	if err := json.NewDecoder(r.Body).Decode(&listReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.validator.Struct(&listReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	list, err := h.listService.UpdateList(r.Context(), userId, id, listReq.ToModel())
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromReadingList(list))
+}
+
+// DeleteList godoc
+// @Tags reading lists
+// @Accept json
+// @Produce json
+// @Summary Delete a reading list
+// @Description The posts on the list and their bookmarks are kept
+// @Param id path string true "Reading list ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /users/me/lists/{id} [delete]
+func (h *readingListHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := h.listService.DeleteList(r.Context(), userId, id); err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusNoContent, "")
+}
+
+// AddPost godoc
+// @Tags reading lists
+// @Accept json
+// @Produce json
+// @Summary Add a post to a reading list
+// @Description Adding a post twice has no effect
+// @Param id path string true "Reading list ID"
+// @Param postId path string true "Post ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /users/me/lists/{id}/posts/{postId} [put]
+func (h *readingListHandler) AddPost(w http.ResponseWriter, r *http.Request) {
+	h.changePost(w, r, true)
+}
+
+// RemovePost godoc
+// @Tags reading lists
+// @Accept json
+// @Produce json
+// @Summary Remove a post from a reading list
+// @Param id path string true "Reading list ID"
+// @Param postId path string true "Post ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /users/me/lists/{id}/posts/{postId} [delete]
+func (h *readingListHandler) RemovePost(w http.ResponseWriter, r *http.Request) {
+	h.changePost(w, r, false)
+}
+
+func (h *readingListHandler) changePost(w http.ResponseWriter, r *http.Request, add bool) {
+	listId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	if add {
+		err = h.listService.AddPost(r.Context(), userId, listId, postId)
+	} else {
+		err = h.listService.RemovePost(r.Context(), userId, listId, postId)
+	}
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusNoContent, "")
+}
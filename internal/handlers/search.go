@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+)
+
+type searchHandler struct {
+	searchService interfaces.SearchService
+}
+
+func NewSearchHandler(searchService interfaces.SearchService) *searchHandler {
+	return &searchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search godoc
+// @Tags search
+// @Accept json
+// @Produce json
+// @Summary Search posts or comments
+// @Description Full-text search ranked by bm25. Words ending with * match as prefixes. Title and snippet are HTML with matches wrapped in <mark>.
+// @Param q query string true "Search terms"
+// @Param type query string false "posts (default) or comments"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {array} dto.SearchResultResp "Empty array if nothing matches"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /search [get]
+func (h *searchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts.ViewerID, _ = utils.GetUserIDFromContext(r)
+
+	query := r.URL.Query()
+	results, next, err := h.searchService.Search(r.Context(), query.Get("q"), query.Get("type"), opts)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrEmptySearch) || errors.Is(err, models.ErrInvalidSearchType) || errors.Is(err, models.ErrInvalidCursor) {
+			status = http.StatusBadRequest
+		}
+		utils.HandleError(w, status, err)
+		return
+	}
+	utils.SetPaginationHeaders(w, r, next)
+	utils.ResponseJSON(w, http.StatusOK, dto.FromSearchResultList(results))
+}
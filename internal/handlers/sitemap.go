@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+)
+
+// sitemapPageSize is the most URLs a sitemap may list. Larger sitemaps are
+// split into pages listed by a sitemap index.
+const sitemapPageSize = 50000
+
+type sitemapHandler struct {
+	sitemapService interfaces.SitemapService
+	baseURL        string
+	robots         []byte
+	robotsModified time.Time
+}
+
+// NewSitemapHandler serves the sitemap and robots.txt. robots.txt is read
+// from robotsFile once, or when that is empty or unreadable, generated from
+// the disallowed paths with a link to the sitemap.
+func NewSitemapHandler(sitemapService interfaces.SitemapService, baseURL, robotsFile string, disallow []string) *sitemapHandler {
+	h := &sitemapHandler{
+		sitemapService: sitemapService,
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		robotsModified: time.Now(),
+	}
+	if robotsFile != "" {
+		robots, err := os.ReadFile(robotsFile)
+		if err == nil {
+			h.robots = robots
+			return h
+		}
+		utils.Log(utils.WARNING, "Failed to read robots file %s: %v", robotsFile, err)
+	}
+
+	var robots strings.Builder
+	robots.WriteString("User-agent: *\n")
+	for _, path := range disallow {
+		fmt.Fprintf(&robots, "Disallow: %s\n", path)
+	}
+	if len(disallow) == 0 {
+		robots.WriteString("Disallow:\n")
+	}
+	fmt.Fprintf(&robots, "\nSitemap: %s/sitemap.xml\n", h.baseURL)
+	h.robots = []byte(robots.String())
+	return h
+}
+
+// Sitemap godoc
+// @Tags seo
+// @Produce xml
+// @Summary Get the sitemap
+// @Description Published posts, the pages of their authors and the tags in use, with the time each last changed. Past 50,000 URLs this is a sitemap index pointing at numbered pages. Supports conditional GET with ETag and Last-Modified.
+// @Param page query int false "Page of a sitemap split by the index"
+// @Success 200 {string} string "Sitemap"
+// @Success 304
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /sitemap.xml [get]
+func (h *sitemapHandler) Sitemap(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.sitemapService.GetEntries(r.Context())
+	if err != nil {
+		utils.HandleError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var document any
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			utils.HandleError(w, http.StatusBadRequest, err)
+			return
+		}
+		start := (page - 1) * sitemapPageSize
+		if page < 1 || start >= max(len(entries), 1) {
+			utils.HandleError(w, http.StatusNotFound, errors.New("sitemap page not found"))
+			return
+		}
+		entries = entries[start:min(start+sitemapPageSize, len(entries))]
+		document = dto.ToSitemapURLSet(h.baseURL, entries)
+	} else if len(entries) > sitemapPageSize {
+		pageURL := func(page int) string {
+			return h.baseURL + "/sitemap.xml?page=" + strconv.Itoa(page)
+		}
+		document = dto.ToSitemapIndex(pageURL, entries, sitemapPageSize)
+	} else {
+		document = dto.ToSitemapURLSet(h.baseURL, entries)
+	}
+
+	body, err := xml.Marshal(document)
+	if err != nil {
+		utils.HandleError(w, http.StatusInternalServerError, err)
+		return
+	}
+	lastMod := time.Unix(0, 0)
+	for _, entry := range entries {
+		if entry.LastMod.After(lastMod) {
+			lastMod = entry.LastMod
+		}
+	}
+	utils.ServeConditional(w, r, "application/xml; charset=utf-8", append([]byte(xml.Header), body...), lastMod)
+}
+
+// Robots godoc
+// @Tags seo
+// @Produce plain
+// @Summary Get robots.txt
+// @Success 200 {string} string "robots.txt"
+// @Router /robots.txt [get]
+func (h *sitemapHandler) Robots(w http.ResponseWriter, r *http.Request) {
+	utils.ServeConditional(w, r, "text/plain; charset=utf-8", h.robots, h.robotsModified)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type spamHandler struct {
+	spamService interfaces.SpamService
+}
+
+func NewSpamHandler(spamService interfaces.SpamService) *spamHandler {
+	return &spamHandler{
+		spamService: spamService,
+	}
+}
+
+// @Summary Spam Classifier Stats
+// @Description Shows how many comments the spam classifier learned from and the tokens that point most strongly to spam. Admins only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.SpamStatsResp
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/spam [get]
+func (h *spamHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.spamService.GetStats(r.Context())
+	if err != nil {
+		utils.HandleError(w, http.StatusInternalServerError, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromSpamStats(stats))
+}
+
+// @Summary Retrain Spam Classifier
+// @Description Rebuilds the spam classifier from every comment currently marked as spam or approved. Admins only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.SpamStatsResp
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/spam/retrain [post]
+func (h *spamHandler) Retrain(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.spamService.Retrain(r.Context())
+	if err != nil {
+		utils.HandleError(w, http.StatusInternalServerError, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromSpamStats(stats))
+}
+
+// @Summary Inspect Comment Spam Score
+// @Description Runs the spam checks on a stored comment and shows what each of them thinks. Admins only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {object} dto.SpamReportResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /admin/spam/comments/{id} [get]
+func (h *spamHandler) InspectComment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	report, err := h.spamService.InspectComment(r.Context(), id)
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromSpamReport(report))
+}
@@ -45,7 +45,7 @@ func (h *userHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	user := userReq.ToModel()
-	createdUser, err := h.userService.RegisterUser(user)
+	createdUser, err := h.userService.RegisterUser(r.Context(), user)
 	if err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
@@ -75,7 +75,7 @@ func (h *userHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.userService.LoginUser(creds.UsernameOrEmail, creds.Password)
+	token, err := h.userService.LoginUser(r.Context(), creds.UsernameOrEmail, creds.Password)
 	if err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
@@ -101,7 +101,7 @@ func (h *userHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	}
 
 	token := strings.TrimPrefix(authHeader, "Bearer ")
-	if err := h.userService.LogoutUser(token); err != nil {
+	if err := h.userService.LogoutUser(r.Context(), token); err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
 	}
@@ -110,30 +110,44 @@ func (h *userHandler) Logout(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Get All Users
-// @Description Lists all users from the database.
+// @Description Lists users page by page using cursor based pagination.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param sort query string false "Sort field: createdAt, username (prefix with - for descending)"
+// @Param from query string false "Registered at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Registered before (RFC3339 or YYYY-MM-DD)"
 // @Success 200 {array} dto.UserResponse "Empty array if no users"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /users [get]
 func (h *userHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.userService.GetAllUsers()
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	users, next, err := h.userService.GetAllUsers(r.Context(), opts)
 	if err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
 	}
 	usersRes := dto.UserListResponse(users)
+	utils.SetPaginationHeaders(w, r, next)
 	utils.ResponseJSON(w, http.StatusOK, usersRes)
 }
 
 // @Summary Get User by ID
-// @Description Retrieves a user by their ID.
+// @Description Retrieves a user by their ID, with their follower and following counts.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
-// @Success 200 {object} dto.UserResponse
+// @Success 200 {object} dto.UserProfileResponse
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
 // @Router /users/{id} [get]
@@ -144,11 +158,39 @@ func (h *userHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
 	}
-	user, err := h.userService.GetUserByID(id)
+	viewerId, _ := utils.GetUserIDFromContext(r)
+	user, err := h.userService.GetUserProfile(r.Context(), viewerId, id)
 	if err != nil {
 		utils.HandleError(w, http.StatusBadRequest, err)
 		return
 	}
-	userRes := dto.UserResponseFromModel(user)
+	userRes := dto.UserProfileResponseFromModel(user)
 	utils.ResponseJSON(w, http.StatusOK, userRes)
 }
+
+// @Summary Delete Current User
+// @Description Deletes the logged in user's account and logs them out. Their posts and comments are kept without an author.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Success 200 {string} string "Account deleted"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /users/me [delete]
+func (h *userHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := h.userService.DeleteUser(r.Context(), userId); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if err := h.userService.LogoutUser(r.Context(), token); err != nil {
+		utils.Log(utils.WARNING, "Failed to revoke token of deleted user %s: %v", userId, err)
+	}
+	utils.ResponseJSON(w, http.StatusOK, "Account deleted")
+}
@@ -0,0 +1,473 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/middlewares"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// webPages are the templates every theme must have besides layout.html.
+var webPages = []string{"home", "post", "author", "tag", "login", "register", "error"}
+
+// csrfCookie holds a random value the CSRF token of logged out visitors'
+// forms is derived from.
+const csrfCookie = "csrf"
+
+var errInvalidCSRF = errors.New("invalid form token, please try again")
+
+type webHandler struct {
+	postService    interfaces.PostService
+	commentService interfaces.CommentService
+	userService    interfaces.UserService
+	validator      *validator.Validate
+	pages          map[string]*template.Template
+	static         http.Handler
+	title          string
+	description    string
+	secureCookie   bool
+	pageSize       int
+	tokenTTL       time.Duration
+}
+
+// webPage is the data every template is executed with. Only the fields of
+// the page being rendered are set.
+type webPage struct {
+	Site        string
+	Description string
+	Title       string
+	Viewer      *models.Author
+	CSRF        string
+	FeedURL     string
+	Next        string
+	Error       string
+	Form        map[string]string
+
+	Posts    []*models.Post
+	Post     *models.Post
+	Comments []*models.Comment
+	Author   *models.User
+	Tag      string
+}
+
+// NewWebHandler serves the HTML frontend from the templates and static
+// files of theme. Each page template is parsed together with layout.html
+// and the partials in templates/partials.
+func NewWebHandler(theme fs.FS, postService interfaces.PostService, commentService interfaces.CommentService, userService interfaces.UserService, baseURL, title, description string, pageSize int, tokenTTL time.Duration) (*webHandler, error) {
+	funcs := template.FuncMap{
+		// Post content is sanitized when its markdown is rendered.
+		"html":      func(content string) template.HTML { return template.HTML(content) },
+		"date":      func(t time.Time) string { return t.Format("January 2, 2006") },
+		"postURL":   func(id uuid.UUID) string { return dto.PostURL("", id) },
+		"authorURL": func(id uuid.UUID) string { return dto.AuthorURL("", id) },
+		"tagURL":    func(tag string) string { return dto.TagURL("", tag) },
+	}
+	layout, err := template.New("").Funcs(funcs).ParseFS(theme, "templates/layout.html")
+	if err != nil {
+		return nil, err
+	}
+	partials, err := fs.Glob(theme, "templates/partials/*.html")
+	if err != nil {
+		return nil, err
+	}
+	if len(partials) > 0 {
+		if layout, err = layout.ParseFS(theme, partials...); err != nil {
+			return nil, err
+		}
+	}
+
+	pages := make(map[string]*template.Template, len(webPages))
+	for _, name := range webPages {
+		page, err := template.Must(layout.Clone()).ParseFS(theme, "templates/"+name+".html")
+		if err != nil {
+			return nil, err
+		}
+		pages[name] = page
+	}
+
+	static, err := fs.Sub(theme, "static")
+	if err != nil {
+		return nil, err
+	}
+
+	return &webHandler{
+		postService:    postService,
+		commentService: commentService,
+		userService:    userService,
+		validator:      validator.New(),
+		pages:          pages,
+		static:         http.StripPrefix("/static/", http.FileServerFS(static)),
+		title:          title,
+		description:    description,
+		secureCookie:   strings.HasPrefix(baseURL, "https://"),
+		pageSize:       pageSize,
+		tokenTTL:       tokenTTL,
+	}, nil
+}
+
+// Static serves the theme's static files under /static/.
+func (h *webHandler) Static(w http.ResponseWriter, r *http.Request) {
+	h.static.ServeHTTP(w, r)
+}
+
+// Home lists the latest published posts.
+func (h *webHandler) Home(w http.ResponseWriter, r *http.Request) {
+	page := h.newPage(r)
+	page.Description = h.description
+	h.listPosts(w, r, "home", page, models.ListOptions{})
+}
+
+// Tag lists the published posts with a tag.
+func (h *webHandler) Tag(w http.ResponseWriter, r *http.Request) {
+	tag := models.NormalizeTag(r.PathValue("tag"))
+	if tag == "" {
+		h.renderError(w, r, http.StatusNotFound, errors.New("tag not found"))
+		return
+	}
+	page := h.newPage(r)
+	page.Title = "#" + tag
+	page.Tag = tag
+	page.FeedURL = "/tags/" + url.PathEscape(tag) + "/feed.xml"
+	h.listPosts(w, r, "tag", page, models.ListOptions{Tag: tag})
+}
+
+// Author shows a user with their published posts.
+func (h *webHandler) Author(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusNotFound, errors.New("user not found"))
+		return
+	}
+	page := h.newPage(r)
+	author, err := h.userService.GetUserProfile(r.Context(), viewerID(page), userId)
+	if err != nil {
+		h.renderError(w, r, http.StatusNotFound, err)
+		return
+	}
+	page.Title = strings.TrimSpace(author.FirstName + " " + author.LastName)
+	page.Author = author
+	page.FeedURL = "/users/" + userId.String() + "/feed.xml"
+	h.listPosts(w, r, "author", page, models.ListOptions{AuthorID: userId})
+}
+
+func (h *webHandler) listPosts(w http.ResponseWriter, r *http.Request, name string, page *webPage, opts models.ListOptions) {
+	opts.Limit = h.pageSize
+	opts.Cursor = r.URL.Query().Get("cursor")
+	opts.Sort = "createdAt"
+	opts.Desc = true
+	posts, next, err := h.postService.GetAllPosts(r.Context(), opts)
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	page.Posts, page.Next = posts, next
+	h.render(w, r, http.StatusOK, name, page)
+}
+
+// Post shows a post with a page of its comments, and a comment form to
+// logged in users.
+func (h *webHandler) Post(w http.ResponseWriter, r *http.Request) {
+	h.renderPost(w, r, http.StatusOK, h.newPage(r))
+}
+
+// CreateComment adds a comment from the form on the post page.
+func (h *webHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	postId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusNotFound, errors.New("post not found"))
+		return
+	}
+	page := h.newPage(r)
+	if page.Viewer == nil {
+		http.Redirect(w, r, "/login?next="+url.QueryEscape(dto.PostURL("", postId)), http.StatusSeeOther)
+		return
+	}
+	if !h.validCSRF(r, page) {
+		h.renderError(w, r, http.StatusForbidden, errInvalidCSRF)
+		return
+	}
+
+	content := strings.TrimSpace(r.PostFormValue("content"))
+	page.Form = map[string]string{"content": content}
+	if content == "" {
+		page.Error = "comment can't be empty"
+		h.renderPost(w, r, http.StatusBadRequest, page)
+		return
+	}
+	comment, err := h.commentService.CreateComment(r.Context(), page.Viewer.ID, &models.Comment{PostID: postId, Content: content})
+	if err != nil {
+		page.Error = err.Error()
+		h.renderPost(w, r, http.StatusBadRequest, page)
+		return
+	}
+	http.Redirect(w, r, dto.PostURL("", postId)+"#comment-"+comment.ID.String(), http.StatusSeeOther)
+}
+
+func (h *webHandler) renderPost(w http.ResponseWriter, r *http.Request, status int, page *webPage) {
+	postId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusNotFound, errors.New("post not found"))
+		return
+	}
+	post, err := h.postService.GetPostByID(r.Context(), viewerID(page), postId)
+	if err != nil {
+		h.renderError(w, r, http.StatusNotFound, err)
+		return
+	}
+	page.Title = post.Title
+	page.Post = post
+	page.Comments, page.Next = post.Comments, post.CommentsNext
+	if cursor := r.URL.Query().Get("comments"); cursor != "" {
+		opts := models.ListOptions{Cursor: cursor, Sort: "thread"}
+		page.Comments, page.Next, err = h.commentService.GetPostComments(r.Context(), viewerID(page), postId, opts)
+		if err != nil {
+			h.renderError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+	h.render(w, r, status, "post", page)
+}
+
+// LoginForm shows the login form.
+func (h *webHandler) LoginForm(w http.ResponseWriter, r *http.Request) {
+	page := h.newPage(r)
+	next := safeRedirect(r.URL.Query().Get("next"))
+	if page.Viewer != nil {
+		http.Redirect(w, r, next, http.StatusSeeOther)
+		return
+	}
+	page.Title = "Log in"
+	page.Form = map[string]string{"next": next}
+	if err := h.guestCSRF(w, page); err != nil {
+		h.renderError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	h.render(w, r, http.StatusOK, "login", page)
+}
+
+// Login logs the user in by keeping their token in a cookie.
+func (h *webHandler) Login(w http.ResponseWriter, r *http.Request) {
+	page := h.newPage(r)
+	if !h.validCSRF(r, page) {
+		h.renderError(w, r, http.StatusForbidden, errInvalidCSRF)
+		return
+	}
+	page.Title = "Log in"
+	page.Form = map[string]string{
+		"username": strings.TrimSpace(r.PostFormValue("username")),
+		"next":     safeRedirect(r.PostFormValue("next")),
+	}
+	token, err := h.userService.LoginUser(r.Context(), page.Form["username"], r.PostFormValue("password"))
+	if err != nil {
+		page.Error = err.Error()
+		h.render(w, r, http.StatusBadRequest, "login", page)
+		return
+	}
+	h.logIn(w, token)
+	http.Redirect(w, r, page.Form["next"], http.StatusSeeOther)
+}
+
+// RegisterForm shows the registration form.
+func (h *webHandler) RegisterForm(w http.ResponseWriter, r *http.Request) {
+	page := h.newPage(r)
+	if page.Viewer != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	page.Title = "Register"
+	if err := h.guestCSRF(w, page); err != nil {
+		h.renderError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	h.render(w, r, http.StatusOK, "register", page)
+}
+
+// Register creates an account and logs the new user in.
+func (h *webHandler) Register(w http.ResponseWriter, r *http.Request) {
+	page := h.newPage(r)
+	if !h.validCSRF(r, page) {
+		h.renderError(w, r, http.StatusForbidden, errInvalidCSRF)
+		return
+	}
+	page.Title = "Register"
+	userReq := dto.UserRequest{
+		FirstName: strings.TrimSpace(r.PostFormValue("firstName")),
+		LastName:  strings.TrimSpace(r.PostFormValue("lastName")),
+		Username:  strings.TrimSpace(r.PostFormValue("username")),
+		Email:     strings.TrimSpace(r.PostFormValue("email")),
+		Password:  r.PostFormValue("password"),
+	}
+	page.Form = map[string]string{
+		"firstName": userReq.FirstName,
+		"lastName":  userReq.LastName,
+		"username":  userReq.Username,
+		"email":     userReq.Email,
+	}
+	if err := h.validator.Struct(&userReq); err != nil {
+		page.Error = validationMessage(err)
+		h.render(w, r, http.StatusBadRequest, "register", page)
+		return
+	}
+	if _, err := h.userService.RegisterUser(r.Context(), userReq.ToModel()); err != nil {
+		page.Error = err.Error()
+		h.render(w, r, http.StatusBadRequest, "register", page)
+		return
+	}
+	token, err := h.userService.LoginUser(r.Context(), userReq.Username, userReq.Password)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	h.logIn(w, token)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// Logout revokes the token in the cookie and clears it.
+func (h *webHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	page := h.newPage(r)
+	if page.Viewer != nil {
+		if !h.validCSRF(r, page) {
+			h.renderError(w, r, http.StatusForbidden, errInvalidCSRF)
+			return
+		}
+		cookie, _ := r.Cookie(middlewares.TokenCookie)
+		if err := h.userService.LogoutUser(r.Context(), cookie.Value); err != nil {
+			utils.Log(utils.WARNING, "Çıkış yapılırken token iptal edilemedi: %v", err)
+		}
+	}
+	h.setCookie(w, middlewares.TokenCookie, "", -1)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// newPage fills in what every page shows: the site and the logged in user
+// with the CSRF token of their forms. The user comes from the login token,
+// so rendering a page doesn't look them up.
+func (h *webHandler) newPage(r *http.Request) *webPage {
+	page := &webPage{Site: h.title}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		if cookie, err := r.Cookie(csrfCookie); err == nil && cookie.Value != "" {
+			page.CSRF = csrfToken(cookie.Value)
+		}
+		return page
+	}
+	username, _ := r.Context().Value(middlewares.UsernameKey).(string)
+	page.Viewer = &models.Author{ID: userId, Username: username}
+	if cookie, err := r.Cookie(middlewares.TokenCookie); err == nil {
+		page.CSRF = csrfToken(cookie.Value)
+	}
+	return page
+}
+
+// guestCSRF gives a logged out visitor the random cookie the CSRF token of
+// the login and registration forms is derived from, unless they have one.
+func (h *webHandler) guestCSRF(w http.ResponseWriter, page *webPage) error {
+	if page.CSRF != "" {
+		return nil
+	}
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return err
+	}
+	h.setCookie(w, csrfCookie, hex.EncodeToString(value), 0)
+	page.CSRF = csrfToken(hex.EncodeToString(value))
+	return nil
+}
+
+// validCSRF checks the form's CSRF token. The token is derived from the
+// login token or, before logging in, the csrf cookie; other sites can read
+// neither.
+func (h *webHandler) validCSRF(r *http.Request, page *webPage) bool {
+	token := r.PostFormValue("csrf")
+	return page.CSRF != "" && subtle.ConstantTimeCompare([]byte(token), []byte(page.CSRF)) == 1
+}
+
+func csrfToken(token string) string {
+	sum := sha256.Sum256([]byte("csrf:" + token))
+	return hex.EncodeToString(sum[:16])
+}
+
+// logIn stores the login token. From then on the CSRF token is derived from
+// it, so the csrf cookie is dropped.
+func (h *webHandler) logIn(w http.ResponseWriter, token string) {
+	h.setCookie(w, middlewares.TokenCookie, token, h.tokenTTL)
+	h.setCookie(w, csrfCookie, "", -1)
+}
+
+// setCookie sets an HTTP only cookie. A zero maxAge keeps it for the browser
+// session and a negative one removes it.
+func (h *webHandler) setCookie(w http.ResponseWriter, name, value string, maxAge time.Duration) {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   h.secureCookie,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   int(maxAge.Seconds()),
+	}
+	if maxAge < 0 {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+}
+
+func (h *webHandler) render(w http.ResponseWriter, r *http.Request, status int, name string, page *webPage) {
+	var body bytes.Buffer
+	if err := h.pages[name].ExecuteTemplate(&body, "layout", page); err != nil {
+		utils.Log(utils.ERROR, "%s sayfası oluşturulamadı: %v", name, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	body.WriteTo(w)
+}
+
+func (h *webHandler) renderError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	page := h.newPage(r)
+	page.Title = http.StatusText(status)
+	page.Error = err.Error()
+	h.render(w, r, status, "error", page)
+}
+
+func viewerID(page *webPage) uuid.UUID {
+	if page.Viewer == nil {
+		return uuid.Nil
+	}
+	return page.Viewer.ID
+}
+
+// safeRedirect only lets redirects go to paths on this site.
+func safeRedirect(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+// validationMessage names the first invalid form field.
+func validationMessage(err error) string {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) == 0 {
+		return err.Error()
+	}
+	return fmt.Sprintf("%s is invalid (%s)", errs[0].Field(), errs[0].Tag())
+}
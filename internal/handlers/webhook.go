@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type webhookHandler struct {
+	webhookService interfaces.WebhookService
+	validator      *validator.Validate
+}
+
+// NewWebhookHandler manages webhooks. Subscribe its Enqueue method to the
+// event bus to queue deliveries.
+func NewWebhookHandler(webhookService interfaces.WebhookService) *webhookHandler {
+	return &webhookHandler{
+		webhookService: webhookService,
+		validator:      validator.New(),
+	}
+}
+
+// GetWebhooks godoc
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Summary Get my webhooks
+// @Success 200 {array} dto.WebhookResp "Empty array if no webhooks"
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /webhooks [get]
+func (h *webhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	webhooks, err := h.webhookService.GetWebhooks(r.Context(), userId)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromWebhookList(webhooks))
+}
+
+// CreateWebhook godoc
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Summary Create a webhook
+// @Description Events are posted as JSON to url, signed with the secret: the X-Webhook-Signature-256 header is "sha256=" followed by the hex HMAC-SHA256 of the body. A secret is generated if none is given; it is only shown in this response.
+// @Description Webhooks hear about the user's own posts and the comments on them. Global webhooks hear about every post and can only be created by admins.
+// @Param webhook body dto.WebhookReq true "Webhook"
+// @Success 201 {object} dto.WebhookCreatedResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /webhooks [post]
+func (h *webhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var webhookReq dto.WebhookReq
+	if err := json.NewDecoder(r.Body).Decode(&webhookReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.validator.Struct(&webhookReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return
+	}
+	webhook, err := h.webhookService.CreateWebhook(r.Context(), userId, webhookReq.ToModel())
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusCreated, &dto.WebhookCreatedResp{
+		WebhookResp: *dto.FromWebhook(webhook),
+		Secret:      webhook.Secret,
+	})
+}
+
+// GetWebhook godoc
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Summary Get a webhook
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} dto.WebhookResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /webhooks/{id} [get]
+func (h *webhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, userId, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	webhook, err := h.webhookService.GetWebhook(r.Context(), userId, id)
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromWebhook(webhook))
+}
+
+// UpdateWebhook godoc
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Summary Update a webhook
+// @Description Replaces the URL, events and flags of a webhook. The secret is kept unless a new one is given.
+// @Param id path string true "Webhook ID"
+// @Param webhook body dto.WebhookReq true "Webhook"
+// @Success 200 {object} dto.WebhookResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /webhooks/{id} [put]
+func (h *webhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, userId, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	var webhookReq dto.WebhookReq
+	if err := json.NewDecoder(r.Body).Decode(&webhookReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.validator.Struct(&webhookReq); err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	webhook, err := h.webhookService.UpdateWebhook(r.Context(), userId, id, webhookReq.ToModel())
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromWebhook(webhook))
+}
+
+// DeleteWebhook godoc
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Summary Delete a webhook
+// @Description Deletes the webhook with its delivery log
+// @Param id path string true "Webhook ID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /webhooks/{id} [delete]
+func (h *webhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, userId, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	if err := h.webhookService.DeleteWebhook(r.Context(), userId, id); err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusNoContent, "")
+}
+
+// GetDeliveries godoc
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Summary Get the delivery log of a webhook
+// @Description Retrieve a page of the webhook's deliveries, newest first
+// @Param id path string true "Webhook ID"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {array} dto.WebhookDeliveryResp "Empty array if no deliveries"
+// @Header 200 {string} Link "Next page link"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /webhooks/{id}/deliveries [get]
+func (h *webhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	opts, err := utils.ParseListOptions(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	id, userId, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	deliveries, next, err := h.webhookService.GetDeliveries(r.Context(), userId, id, opts)
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.SetPaginationHeaders(w, r, next)
+	utils.ResponseJSON(w, http.StatusOK, dto.FromWebhookDeliveryList(deliveries))
+}
+
+// GetDelivery godoc
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Summary Get a webhook delivery
+// @Description The delivery with its payload and the receiver's last response
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 200 {object} dto.WebhookDeliveryDetailResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /webhooks/{id}/deliveries/{deliveryId} [get]
+func (h *webhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	id, userId, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	deliveryId, err := uuid.Parse(r.PathValue("deliveryId"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	delivery, err := h.webhookService.GetDelivery(r.Context(), userId, id, deliveryId)
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusOK, dto.FromWebhookDeliveryDetail(delivery))
+}
+
+// Redeliver godoc
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Summary Redeliver a webhook delivery
+// @Description Queues the payload of a past delivery to be sent again as a new delivery
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} dto.WebhookDeliveryResp
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *webhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, userId, ok := parseWebhookPath(w, r)
+	if !ok {
+		return
+	}
+	deliveryId, err := uuid.Parse(r.PathValue("deliveryId"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return
+	}
+	delivery, err := h.webhookService.Redeliver(r.Context(), userId, id, deliveryId)
+	if err != nil {
+		utils.HandleError(w, http.StatusNotFound, err)
+		return
+	}
+	utils.ResponseJSON(w, http.StatusAccepted, dto.FromWebhookDelivery(delivery))
+}
+
+func parseWebhookPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.HandleError(w, http.StatusBadRequest, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userId, err := utils.GetUserIDFromContext(r)
+	if err != nil {
+		utils.HandleError(w, http.StatusUnauthorized, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, userId, true
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+type BackupRepository interface {
+	Create(ctx context.Context) (*models.Backup, error)
+	GetAll(ctx context.Context) ([]*models.Backup, error)
+}
+
+type BackupService interface {
+	CreateBackup(ctx context.Context) (*models.Backup, error)
+	GetAllBackups(ctx context.Context) ([]*models.Backup, error)
+}
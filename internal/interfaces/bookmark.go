@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type BookmarkRepository interface {
+	Add(ctx context.Context, userID, postID uuid.UUID) error
+	Remove(ctx context.Context, userID, postID uuid.UUID) error
+	GetBookmarked(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+}
+
+type BookmarkService interface {
+	AddBookmark(ctx context.Context, userId, postId uuid.UUID) error
+	RemoveBookmark(ctx context.Context, userId, postId uuid.UUID) error
+	GetBookmarks(ctx context.Context, userId uuid.UUID, opts models.ListOptions) ([]*models.Post, string, error)
+}
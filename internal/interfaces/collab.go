@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type CoAuthorRepository interface {
+	Add(ctx context.Context, postID, userID uuid.UUID) error
+	Remove(ctx context.Context, postID, userID uuid.UUID) error
+	IsCoAuthor(ctx context.Context, postID, userID uuid.UUID) (bool, error)
+	GetByPostID(ctx context.Context, postID uuid.UUID) ([]*models.CoAuthor, error)
+}
+
+type CollabService interface {
+	GetCoAuthors(ctx context.Context, viewerId, postId uuid.UUID) ([]*models.CoAuthor, error)
+	AddCoAuthor(ctx context.Context, userId, postId, coAuthorId uuid.UUID) error
+	RemoveCoAuthor(ctx context.Context, userId, postId, coAuthorId uuid.UUID) error
+	Join(ctx context.Context, userId, postId uuid.UUID) (CollabSession, error)
+}
+
+// CollabSession is one connection to the collaborative editor of a draft.
+// Messages is closed when the session ends, either through Leave or because
+// the server dropped it; the client should then reconnect to get a fresh
+// snapshot.
+type CollabSession interface {
+	Messages() <-chan *models.CollabMessage
+	Apply(revision int, op models.TextOperation) error
+	MoveCursor(cursor models.CollabCursor)
+	Leave()
+}
@@ -1,22 +1,35 @@
 package interfaces
 
 import (
+	"context"
+	"time"
+
 	"github.com/ahmetilboga2004/go-blog/internal/models"
 	"github.com/google/uuid"
 )
 
 type CommentRepository interface {
-	Create(comment *models.Comment) (*models.Comment, error)
-	GetAll() ([]*models.Comment, error)
-	GetByID(id uuid.UUID) (*models.Comment, error)
-	Update(id uuid.UUID, comment *models.Comment) (*models.Comment, error)
-	Delete(id uuid.UUID) error
+	Create(ctx context.Context, comment *models.Comment) (*models.Comment, error)
+	GetAll(ctx context.Context, opts models.ListOptions) ([]*models.Comment, string, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Comment, error)
+	GetByStatus(ctx context.Context, status string, opts models.ListOptions) ([]*models.Comment, string, error)
+	CountByPostIDs(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	HasApproved(ctx context.Context, userID uuid.UUID) (bool, error)
+	CountByUserSince(ctx context.Context, userID uuid.UUID, from, to time.Time) (int, error)
+	SetStatus(ctx context.Context, ids []uuid.UUID, status string) (int, error)
+	Update(ctx context.Context, id uuid.UUID, comment *models.Comment) (*models.Comment, error)
+	HasReplies(ctx context.Context, id uuid.UUID) (bool, error)
+	SoftDelete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type CommentService interface {
-	CreateComment(userId uuid.UUID, comment *models.Comment) (*models.Comment, error)
-	GetCommentByID(id uuid.UUID) (*models.Comment, error)
-	GetAllComments() ([]*models.Comment, error)
-	UpdateComment(userId, commentId uuid.UUID, comment *models.Comment) (*models.Comment, error)
-	DeleteComment(userId, commentId uuid.UUID) error
+	CreateComment(ctx context.Context, userId uuid.UUID, comment *models.Comment) (*models.Comment, error)
+	GetCommentByID(ctx context.Context, viewerId, id uuid.UUID) (*models.Comment, error)
+	GetAllComments(ctx context.Context, opts models.ListOptions) ([]*models.Comment, string, error)
+	GetPostComments(ctx context.Context, viewerId, postId uuid.UUID, opts models.ListOptions) ([]*models.Comment, string, error)
+	UpdateComment(ctx context.Context, userId, commentId uuid.UUID, comment *models.Comment) (*models.Comment, error)
+	DeleteComment(ctx context.Context, userId, commentId uuid.UUID) error
+	GetModerationQueue(ctx context.Context, status string, opts models.ListOptions) ([]*models.Comment, string, error)
+	ModerateComments(ctx context.Context, ids []uuid.UUID, status string) (int, error)
 }
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+type EventHandler func(ctx context.Context, event *models.Event) error
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event *models.Event)
+}
+
+type EventBus interface {
+	EventPublisher
+	Subscribe(name string, handler EventHandler)
+}
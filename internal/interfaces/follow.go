@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type FollowRepository interface {
+	Follow(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error)
+	Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error
+	IsFollowing(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error)
+	GetFollowerIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	GetCounts(ctx context.Context, userID uuid.UUID) (followers int, following int, err error)
+	GetFollowers(ctx context.Context, userID uuid.UUID, opts models.ListOptions) ([]*models.Follow, string, error)
+	GetFollowing(ctx context.Context, userID uuid.UUID, opts models.ListOptions) ([]*models.Follow, string, error)
+}
+
+type FollowService interface {
+	Follow(ctx context.Context, userId, followeeId uuid.UUID) error
+	Unfollow(ctx context.Context, userId, followeeId uuid.UUID) error
+	GetFollowers(ctx context.Context, userId uuid.UUID, opts models.ListOptions) ([]*models.Follow, string, error)
+	GetFollowing(ctx context.Context, userId uuid.UUID, opts models.ListOptions) ([]*models.Follow, string, error)
+}
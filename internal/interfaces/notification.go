@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	FilterEnabled(ctx context.Context, notificationType string, userIDs []uuid.UUID) ([]uuid.UUID, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, opts models.ListOptions) ([]*models.Notification, string, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error)
+	GetPreferences(ctx context.Context, userID uuid.UUID) (map[string]bool, error)
+	SetPreference(ctx context.Context, userID uuid.UUID, notificationType string, enabled bool) error
+}
+
+type NotificationService interface {
+	HandleEvent(ctx context.Context, event *models.Event) error
+	GetNotifications(ctx context.Context, userId uuid.UUID, unreadOnly bool, opts models.ListOptions) ([]*models.Notification, string, error)
+	CountUnread(ctx context.Context, userId uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userId, id uuid.UUID) error
+	MarkAllRead(ctx context.Context, userId uuid.UUID) (int, error)
+	GetPreferences(ctx context.Context, userId uuid.UUID) (map[string]bool, error)
+	UpdatePreferences(ctx context.Context, userId uuid.UUID, preferences map[string]bool) (map[string]bool, error)
+}
@@ -1,22 +1,29 @@
 package interfaces
 
 import (
+	"context"
+
 	"github.com/ahmetilboga2004/go-blog/internal/models"
 	"github.com/google/uuid"
 )
 
 type PostRepository interface {
-	Create(post *models.Post) (*models.Post, error)
-	GetAll() ([]*models.Post, error)
-	GetByID(id uuid.UUID) (*models.Post, error)
-	Update(id uuid.UUID, post *models.Post) (*models.Post, error)
-	Delete(id uuid.UUID) error
+	Create(ctx context.Context, post *models.Post) (*models.Post, error)
+	GetAll(ctx context.Context, opts models.ListOptions) ([]*models.Post, string, error)
+	GetFeed(ctx context.Context, userID uuid.UUID, opts models.ListOptions) ([]*models.Post, string, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Post, error)
+	Update(ctx context.Context, id uuid.UUID, post *models.Post) (*models.Post, error)
+	UpdateCommentSettings(ctx context.Context, id uuid.UUID, policy string, closed bool) error
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type PostService interface {
-	CreatePost(userId uuid.UUID, post *models.Post) (*models.Post, error)
-	GetPostByID(id uuid.UUID) (*models.Post, error)
-	GetAllPosts() ([]*models.Post, error)
-	UpdatePost(userId, postId uuid.UUID, post *models.Post) (*models.Post, error)
-	DeletePost(userId, postId uuid.UUID) error
+	CreatePost(ctx context.Context, userId uuid.UUID, post *models.Post) (*models.Post, error)
+	GetPostByID(ctx context.Context, viewerId, id uuid.UUID) (*models.Post, error)
+	GetAllPosts(ctx context.Context, opts models.ListOptions) ([]*models.Post, string, error)
+	ListPosts(ctx context.Context, opts models.ListOptions) ([]*models.Post, string, error)
+	GetFeed(ctx context.Context, userId uuid.UUID, opts models.ListOptions) ([]*models.Post, string, error)
+	UpdatePost(ctx context.Context, userId, postId uuid.UUID, post *models.Post) (*models.Post, error)
+	UpdateCommentSettings(ctx context.Context, userId, postId uuid.UUID, policy string, closed bool) (*models.Post, error)
+	DeletePost(ctx context.Context, userId, postId uuid.UUID) error
 }
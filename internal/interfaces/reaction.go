@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type ReactionRepository interface {
+	Add(ctx context.Context, target string, targetID, userID uuid.UUID, reaction string) error
+	Remove(ctx context.Context, target string, targetID, userID uuid.UUID, reaction string) error
+	GetCounts(ctx context.Context, target string, targetIDs []uuid.UUID) (map[uuid.UUID]map[string]int, error)
+	GetUserReactions(ctx context.Context, target string, userID uuid.UUID, targetIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+}
+
+type ReactionService interface {
+	React(ctx context.Context, userId uuid.UUID, target string, targetId uuid.UUID, reaction string) (*models.Reactions, error)
+	Unreact(ctx context.Context, userId uuid.UUID, target string, targetId uuid.UUID, reaction string) (*models.Reactions, error)
+}
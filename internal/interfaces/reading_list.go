@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type ReadingListRepository interface {
+	Create(ctx context.Context, list *models.ReadingList) (*models.ReadingList, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ReadingList, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, publicOnly bool) ([]*models.ReadingList, error)
+	Update(ctx context.Context, id uuid.UUID, list *models.ReadingList) (*models.ReadingList, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	AddPost(ctx context.Context, listID, postID uuid.UUID) error
+	RemovePost(ctx context.Context, listID, postID uuid.UUID) error
+}
+
+type ReadingListService interface {
+	CreateList(ctx context.Context, userId uuid.UUID, list *models.ReadingList) (*models.ReadingList, error)
+	GetList(ctx context.Context, viewerId, id uuid.UUID) (*models.ReadingList, error)
+	GetUserLists(ctx context.Context, viewerId, userId uuid.UUID) ([]*models.ReadingList, error)
+	UpdateList(ctx context.Context, userId, id uuid.UUID, list *models.ReadingList) (*models.ReadingList, error)
+	DeleteList(ctx context.Context, userId, id uuid.UUID) error
+	AddPost(ctx context.Context, userId, listId, postId uuid.UUID) error
+	RemovePost(ctx context.Context, userId, listId, postId uuid.UUID) error
+	GetListPosts(ctx context.Context, viewerId, listId uuid.UUID, opts models.ListOptions) ([]*models.Post, string, error)
+}
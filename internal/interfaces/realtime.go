@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+type RealtimeHub interface {
+	Publish(ctx context.Context, message *models.RealtimeMessage) error
+	// Subscribe returns the messages of the topics and a function that ends
+	// the subscription. The channel is closed when the subscription ends,
+	// including when the hub drops a subscriber that fell behind.
+	Subscribe(topics ...string) (<-chan *models.RealtimeMessage, func())
+}
@@ -1,8 +1,11 @@
 package interfaces
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type RedisService interface {
-	BlacklistToken(token string, expiration time.Duration) error
-	IsBlacklistedToken(token string) (bool, error)
+	BlacklistToken(ctx context.Context, token string, expiration time.Duration) error
+	IsBlacklistedToken(ctx context.Context, token string) (bool, error)
 }
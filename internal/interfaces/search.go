@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+type SearchRepository interface {
+	SearchPosts(ctx context.Context, query string, opts models.ListOptions) ([]*models.SearchResult, string, error)
+	SearchComments(ctx context.Context, query string, opts models.ListOptions) ([]*models.SearchResult, string, error)
+}
+
+type SearchService interface {
+	Search(ctx context.Context, query, kind string, opts models.ListOptions) ([]*models.SearchResult, string, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+type SitemapRepository interface {
+	GetEntries(ctx context.Context) ([]*models.SitemapEntry, error)
+}
+
+type SitemapService interface {
+	GetEntries(ctx context.Context) ([]*models.SitemapEntry, error)
+	HandleEvent(ctx context.Context, event *models.Event) error
+}
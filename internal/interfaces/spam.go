@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+// SpamCheck is one step of the spam pipeline that runs before a comment is
+// stored.
+type SpamCheck interface {
+	Name() string
+	Check(ctx context.Context, comment *models.Comment) (models.SpamCheckResult, error)
+}
+
+type SpamRepository interface {
+	GetTokens(ctx context.Context, tokens []string) (map[string]models.SpamToken, error)
+	AdjustTokens(ctx context.Context, tokens []string, spam bool, delta int) error
+	GetTraining(ctx context.Context, commentID uuid.UUID) (label string, tokens []string, err error)
+	SaveTraining(ctx context.Context, commentID uuid.UUID, label string, tokens []string) error
+	GetTrainingSet(ctx context.Context) ([]*models.SpamSample, error)
+	GetStats(ctx context.Context, topTokens int) (*models.SpamStats, error)
+	Reset(ctx context.Context) error
+}
+
+type SpamService interface {
+	Check(ctx context.Context, comment *models.Comment) (*models.SpamReport, error)
+	InspectComment(ctx context.Context, commentId uuid.UUID) (*models.SpamReport, error)
+	Learn(ctx context.Context, comments []*models.Comment, spam bool) error
+	Retrain(ctx context.Context) (*models.SpamStats, error)
+	GetStats(ctx context.Context) (*models.SpamStats, error)
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "context"
+
+// TxManager runs work that spans several repositories atomically. Repository
+// calls made with the context passed to fn join the transaction.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -1,29 +1,34 @@
 package interfaces
 
 import (
+	"context"
+
 	"github.com/ahmetilboga2004/go-blog/internal/models"
 	"github.com/google/uuid"
 )
 
 type UserRepository interface {
-	Create(user *models.User) (*models.User, error)
-	GetAll() ([]*models.User, error)
-	GetByID(id uuid.UUID) (*models.User, error)
-	FindByUsernameOrEmail(username, email string) (*models.User, error)
-	Update(id uuid.UUID, user *models.User) (*models.User, error)
-	Delete(id uuid.UUID) error
-	// UpdatePassword(id uuid.UUID, hashedPassword, salt string) error
+	Create(ctx context.Context, user *models.User) (*models.User, error)
+	GetAll(ctx context.Context, opts models.ListOptions) ([]*models.User, string, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	FindByUsernameOrEmail(ctx context.Context, username, email string) (*models.User, error)
+	Update(ctx context.Context, id uuid.UUID, user *models.User) (*models.User, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	UpdateRole(ctx context.Context, id uuid.UUID, role string) error
+	// UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword, salt string) error
 }
 
 type UserService interface {
-	RegisterUser(user *models.User) (*models.User, error)
-	LoginUser(usernameOrEmail, password string) (string, error)
-	LogoutUser(token string) error
-	GetUserByID(id uuid.UUID) (*models.User, error)
-	// UpdateUser(id uuid.UUID, user *models.User) (*models.User, error)
-	// DeleteUser(id uuid.UUID) error
-	// ChangePassword(id uuid.UUID, oldPassword, newPassword string) error
-	// ResetPassword(email string) error
-	// VerifyEmail(token string) error
-	GetAllUsers() ([]*models.User, error)
+	RegisterUser(ctx context.Context, user *models.User) (*models.User, error)
+	LoginUser(ctx context.Context, usernameOrEmail, password string) (string, error)
+	LogoutUser(ctx context.Context, token string) error
+	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetUserProfile(ctx context.Context, viewerId, id uuid.UUID) (*models.User, error)
+	// UpdateUser(ctx context.Context, id uuid.UUID, user *models.User) (*models.User, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	SetUserRole(ctx context.Context, usernameOrEmail, role string) (*models.User, error)
+	// ChangePassword(ctx context.Context, id uuid.UUID, oldPassword, newPassword string) error
+	// ResetPassword(ctx context.Context, email string) error
+	// VerifyEmail(ctx context.Context, token string) error
+	GetAllUsers(ctx context.Context, opts models.ListOptions) ([]*models.User, string, error)
 }
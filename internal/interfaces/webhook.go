@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error)
+	GetSubscribed(ctx context.Context, event string, ownerID uuid.UUID) ([]*models.Webhook, error)
+	Update(ctx context.Context, id uuid.UUID, webhook *models.Webhook) (*models.Webhook, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error)
+	GetByWebhookID(ctx context.Context, webhookID uuid.UUID, opts models.ListOptions) ([]*models.WebhookDelivery, string, error)
+	GetDue(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error)
+	Claim(ctx context.Context, delivery *models.WebhookDelivery, now, until time.Time) (bool, error)
+	SaveAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+type WebhookService interface {
+	GetWebhooks(ctx context.Context, userId uuid.UUID) ([]*models.Webhook, error)
+	GetWebhook(ctx context.Context, userId, id uuid.UUID) (*models.Webhook, error)
+	CreateWebhook(ctx context.Context, userId uuid.UUID, webhook *models.Webhook) (*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, userId, id uuid.UUID, webhook *models.Webhook) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, userId, id uuid.UUID) error
+	GetDeliveries(ctx context.Context, userId, webhookId uuid.UUID, opts models.ListOptions) ([]*models.WebhookDelivery, string, error)
+	GetDelivery(ctx context.Context, userId, webhookId, id uuid.UUID) (*models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, userId, webhookId, id uuid.UUID) (*models.WebhookDelivery, error)
+	Enqueue(ctx context.Context, event *models.Event) error
+	DeliverDue(ctx context.Context) error
+}
@@ -3,24 +3,36 @@ package middlewares
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
 )
 
 type contextKey string
 
 const UserIDKey contextKey = "userId"
 
+// UsernameKey holds the username the HTML frontend's token was issued for,
+// so pages can show who is logged in without looking the user up.
+const UsernameKey contextKey = "username"
+
+// TokenCookie is the cookie the HTML frontend keeps the login token in.
+const TokenCookie = "token"
+
 type authMiddleware struct {
 	jwtService   interfaces.JWTService
 	redisService interfaces.RedisService
+	userService  interfaces.UserService
 }
 
-func NewAuthMiddleware(jwtService interfaces.JWTService, redisService interfaces.RedisService) *authMiddleware {
+func NewAuthMiddleware(jwtService interfaces.JWTService, redisService interfaces.RedisService, userService interfaces.UserService) *authMiddleware {
 	return &authMiddleware{
 		jwtService:   jwtService,
 		redisService: redisService,
+		userService:  userService,
 	}
 }
 
@@ -33,26 +45,63 @@ func (m *authMiddleware) Auth(next http.Handler) http.Handler {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		next.ServeHTTP(w, m.authenticate(r, tokenString))
+	})
+}
 
-		isBlacklisted, err := m.redisService.IsBlacklistedToken(tokenString)
-		if err != nil || isBlacklisted {
+// QueryToken authenticates requests that carry their token in the
+// access_token query parameter, for clients such as browser WebSockets that
+// can't set the Authorization header. The token is checked just like in Auth.
+func (m *authMiddleware) QueryToken(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString := r.URL.Query().Get("access_token")
+		if tokenString == "" || r.Context().Value(UserIDKey) != nil {
 			next.ServeHTTP(w, r)
 			return
 		}
+		next.ServeHTTP(w, m.authenticate(r, tokenString))
+	})
+}
 
-		userID, err := m.jwtService.ValidateToken(tokenString)
-		if err != nil {
+// CookieToken authenticates requests of the HTML frontend, which carry
+// their token in the TokenCookie cookie. The token is checked just like in
+// Auth; the username it was issued for is added under UsernameKey.
+func (m *authMiddleware) CookieToken(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(TokenCookie)
+		if err != nil || cookie.Value == "" || r.Context().Value(UserIDKey) != nil {
 			next.ServeHTTP(w, r)
 			return
 		}
-
-		ctx := context.WithValue(r.Context(), UserIDKey, userID)
-		r = r.WithContext(ctx)
-
+		r = m.authenticate(r, cookie.Value)
+		if r.Context().Value(UserIDKey) != nil {
+			if claims, err := m.jwtService.ParseTokenClaims(cookie.Value); err == nil {
+				if username, ok := claims["username"].(string); ok {
+					r = r.WithContext(context.WithValue(r.Context(), UsernameKey, username))
+				}
+			}
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
+// authenticate adds the user of a valid, not revoked token to the request
+// context. Requests with other tokens are returned as they are.
+func (m *authMiddleware) authenticate(r *http.Request, tokenString string) *http.Request {
+	isBlacklisted, err := m.redisService.IsBlacklistedToken(r.Context(), tokenString)
+	if err != nil || isBlacklisted {
+		return r
+	}
+
+	userID, err := m.jwtService.ValidateToken(tokenString)
+	if err != nil {
+		return r
+	}
+
+	ctx := context.WithValue(r.Context(), UserIDKey, userID)
+	return r.WithContext(ctx)
+}
+
 func (m *authMiddleware) RequireLogin(next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		userID := r.Context().Value(UserIDKey)
@@ -74,3 +123,32 @@ func (m *authMiddleware) GuestOnly(next http.HandlerFunc) http.HandlerFunc {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RequireAdmin only lets administrators through. The role is read from the
+// database on every request so a demotion takes effect immediately.
+func (m *authMiddleware) RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return m.requireRole(next, models.RoleAdmin)
+}
+
+// RequireModerator lets moderators and administrators through.
+func (m *authMiddleware) RequireModerator(next http.HandlerFunc) http.HandlerFunc {
+	return m.requireRole(next, models.RoleModerator, models.RoleAdmin)
+}
+
+func (m *authMiddleware) requireRole(next http.HandlerFunc, roles ...string) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawUserID, _ := r.Context().Value(UserIDKey).(string)
+		userID, err := uuid.Parse(rawUserID)
+		if err != nil {
+			http.Error(w, "You must be logged in to access this resource", http.StatusUnauthorized)
+			return
+		}
+
+		user, err := m.userService.GetUserByID(r.Context(), userID)
+		if err != nil || !slices.Contains(roles, user.Role) {
+			http.Error(w, "You are not allowed to access this resource", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Timeout gives every request a deadline. Database and Redis calls made with
+// the request context stop when it passes, just as they do when the client
+// disconnects. A zero timeout disables the deadline. Requests matching one of
+// the exempt ServeMux patterns, such as long lived event streams and
+// WebSockets, get no deadline.
+func Timeout(timeout time.Duration, next http.Handler, exempt ...string) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+	exempted := http.NewServeMux()
+	for _, pattern := range exempt {
+		exempted.Handle(pattern, next)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := exempted.Handler(r); pattern != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+type Backup struct {
+	Name      string
+	Size      int64
+	CreatedAt time.Time
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadingList is a named collection of posts. Private lists are only visible
+// to their owner.
+type ReadingList struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	Description string
+	IsPublic    bool
+	PostCount   int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// VisibleTo reports whether the viewer may see the list.
+func (l *ReadingList) VisibleTo(viewerId uuid.UUID) bool {
+	return l.IsPublic || l.UserID == viewerId
+}
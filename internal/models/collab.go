@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CoAuthor is a user the author of a post invited to edit it with them.
+type CoAuthor struct {
+	User      Author
+	CreatedAt time.Time
+}
+
+// TextOperation is an edit of a whole document: its components walk the
+// document from start to end, retaining, inserting or deleting text. Lengths
+// and positions count Unicode code points.
+type TextOperation []TextComponent
+
+// TextComponent sets exactly one of its fields.
+type TextComponent struct {
+	Retain int
+	Insert string
+	Delete int
+}
+
+// Messages exchanged on a collaborative editing channel.
+const (
+	CollabMessageSnapshot  = "snapshot"  // the document and who is editing it, sent on join
+	CollabMessageOperation = "operation" // an edit by another collaborator
+	CollabMessageAck       = "ack"       // the sender's edit was applied
+	CollabMessageCursor    = "cursor"    // a collaborator moved their cursor
+	CollabMessageJoin      = "join"
+	CollabMessageLeave     = "leave"
+)
+
+// CollabCursor is a caret position, or a selection when SelectionEnd differs
+// from Position.
+type CollabCursor struct {
+	Position     int
+	SelectionEnd int
+}
+
+// Collaborator is one connection editing a draft. A user editing from two
+// tabs shows up twice, with different client IDs.
+type Collaborator struct {
+	ClientID uuid.UUID
+	User     Author
+	Cursor   *CollabCursor
+}
+
+// CollabMessage is sent to the collaborators on a draft. Only the fields
+// relevant to its type are set: Revision, Title, Content and Collaborators for
+// snapshots, Revision, Operation and Collaborator for operations, Revision for
+// acks, and Collaborator for cursors, joins and leaves.
+type CollabMessage struct {
+	Type          string
+	Revision      int
+	Title         string
+	Content       string
+	Operation     TextOperation
+	Collaborator  *Collaborator
+	Collaborators []*Collaborator
+}
@@ -1,10 +1,51 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+// Comment policies decide whether a new comment is approved right away. A
+// post can override the site wide policy; an empty policy means it doesn't.
+const (
+	CommentPolicyAuto      = "auto"
+	CommentPolicyFirstTime = "first_time" // hold comments from users without an approved comment
+	CommentPolicyModerate  = "moderate"   // hold every comment
+)
+
+// DeletedCommentContent replaces the content of a deleted comment that is
+// kept so the replies under it stay in their thread.
+const DeletedCommentContent = "[deleted]"
 
 type Comment struct {
-	ID      uuid.UUID `json:"id"`
-	Content string    `json:"content"`
-	UserID  uuid.UUID `json:"userId"`
-	PostID  uuid.UUID `json:"postId"`
+	ID         uuid.UUID  `json:"id"`
+	Content    string     `json:"content"`
+	UserID     uuid.UUID  `json:"userId"`
+	PostID     uuid.UUID  `json:"postId"`
+	ParentID   *uuid.UUID `json:"parentId"`
+	Depth      int        `json:"depth"`
+	Path       string     `json:"path"`
+	Deleted    bool       `json:"deleted"`
+	Status     string     `json:"status"`
+	SpamScore  float64    `json:"spamScore"`
+	ApprovedAt *time.Time `json:"approvedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	Author     Author     `json:"author"`
+	Reactions  Reactions  `json:"reactions"`
+}
+
+// VisibleTo reports whether the viewer may see the comment: comments that
+// aren't approved are only visible to their author. The comment of a deleted
+// user has no author, which must not make it visible to anonymous viewers.
+func (c *Comment) VisibleTo(viewerId uuid.UUID) bool {
+	return c.Status == CommentStatusApproved || (viewerId != uuid.Nil && c.UserID == viewerId)
 }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain events published by the services after a change is committed.
+const (
+	EventCommentCreated      = "comment.created" // a comment became visible, on creation or approval
+	EventPostPublished       = "post.published"
+	EventPostUpdated         = "post.updated"     // a published post was edited
+	EventPostDeleted         = "post.deleted"     // a published post was deleted
+	EventPostUnpublished     = "post.unpublished" // a published post went back to draft
+	EventUserFollowed        = "user.followed"
+	EventReactionChanged     = "reaction.changed"
+	EventNotificationCreated = "notification.created"
+)
+
+// Event describes something that happened. Only the fields relevant to its
+// type are set: Post and Comment for comments, Post for posts (as it was
+// before deletion for post.deleted), UserID (the followed user) for follows,
+// Post, Comment (for comment reactions) and Reactions for reactions, and
+// UserID (the recipient) and Notification for notifications.
+type Event struct {
+	Type         string
+	ActorID      uuid.UUID
+	Post         *Post
+	Comment      *Comment
+	UserID       uuid.UUID
+	Reactions    *Reactions
+	Notification *Notification
+	OccurredAt   time.Time
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// Follow is one entry of a follower or following list: the user on the other
+// side of the follow and when it happened.
+type Follow struct {
+	User      Author
+	CreatedAt time.Time
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned for a cursor that no list handed out.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type ListOptions struct {
+	Limit        int
+	Cursor       string
+	Sort         string
+	Desc         bool
+	AuthorID     uuid.UUID
+	PostID       uuid.UUID
+	ViewerID     uuid.UUID
+	BookmarkedBy uuid.UUID
+	ListID       uuid.UUID
+	Tag          string
+	From         time.Time
+	To           time.Time
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NotificationComment = "comment" // someone commented on your post
+	NotificationReply   = "reply"   // someone replied to your comment
+	NotificationFollow  = "follow"  // someone followed you
+	NotificationPost    = "post"    // an author you follow published a post
+)
+
+// NotificationTypes lists every notification type, in the order preferences
+// are shown.
+var NotificationTypes = []string{NotificationComment, NotificationReply, NotificationFollow, NotificationPost}
+
+type Notification struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Type      string
+	ActorID   *uuid.UUID
+	Actor     Author
+	PostID    *uuid.UUID
+	PostTitle string
+	CommentID *uuid.UUID
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
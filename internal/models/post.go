@@ -1,11 +1,46 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PostStatusDraft     = "draft"
+	PostStatusPublished = "published"
+)
 
 type Post struct {
-	ID       uuid.UUID
-	Title    string
-	Content  string
-	UserID   uuid.UUID
-	Comments []Comment
+	ID             uuid.UUID
+	Title          string
+	Content        string
+	ContentHTML    string
+	Status         string
+	Tags           []string
+	UserID         uuid.UUID
+	CommentPolicy  string
+	CommentsClosed bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Author         Author
+	CommentCount   int
+	Comments       []*Comment
+	CommentsNext   string
+	Reactions      Reactions
+	IsBookmarked   bool
+}
+
+// NormalizeTag lowercases a tag and joins its words with hyphens, so
+// "Go  Tips" and "go-tips" are the same tag.
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// VisibleTo reports whether the viewer may see the post: drafts are only
+// visible to their author. The post of a deleted user has no author, which
+// must not make it visible to anonymous viewers.
+func (p *Post) VisibleTo(viewerId uuid.UUID) bool {
+	return p.Status != PostStatusDraft || (viewerId != uuid.Nil && p.UserID == viewerId)
 }
@@ -0,0 +1,17 @@
+package models
+
+// ReactionLike is always available; the other reaction types come from the
+// configuration.
+const ReactionLike = "like"
+
+const (
+	ReactionTargetPost    = "post"
+	ReactionTargetComment = "comment"
+)
+
+// Reactions summarizes the reactions on a post or comment: how many of each
+// type it has and which ones the viewer left.
+type Reactions struct {
+	Counts map[string]int
+	Mine   []string
+}
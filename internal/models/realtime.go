@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// RealtimeMessage is pushed to the clients subscribed to Topic as a
+// Server-Sent Event named Event.
+type RealtimeMessage struct {
+	Topic string          `json:"topic"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// PostTopic carries new comments and reaction changes on a post.
+func PostTopic(postId uuid.UUID) string {
+	return "post:" + postId.String()
+}
+
+// UserTopic carries a user's notifications.
+func UserTopic(userId uuid.UUID) string {
+	return "user:" + userId.String()
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SearchTypePost    = "post"
+	SearchTypeComment = "comment"
+)
+
+// Errors for searches that can't be run as asked.
+var (
+	ErrEmptySearch       = errors.New("search query is required")
+	ErrInvalidSearchType = errors.New("invalid search type")
+)
+
+type SearchResult struct {
+	Type      string
+	ID        uuid.UUID
+	PostID    uuid.UUID
+	Title     string
+	Snippet   string
+	Rank      float64
+	CreatedAt time.Time
+}
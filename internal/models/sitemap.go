@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SitemapPost   = "post"
+	SitemapAuthor = "author"
+	SitemapTag    = "tag"
+)
+
+// SitemapEntry is a public page for search engines to crawl: a published
+// post, the page of an author with published posts, or a tag in use. LastMod
+// is when its content last changed.
+type SitemapEntry struct {
+	Kind    string
+	ID      uuid.UUID
+	Tag     string
+	LastMod time.Time
+}
@@ -0,0 +1,46 @@
+package models
+
+import "github.com/google/uuid"
+
+// Spam verdicts tell CreateComment what to do with a comment: leave it to the
+// moderation policy, hold it for review or mark it as spam.
+const (
+	SpamVerdictHam    = "ham"
+	SpamVerdictReview = "review"
+	SpamVerdictSpam   = "spam"
+)
+
+// SpamCheckResult is the opinion of one check in the spam pipeline. Score
+// runs from 0 (clean) to 1 (certainly spam).
+type SpamCheckResult struct {
+	Check  string
+	Score  float64
+	Reason string
+}
+
+// SpamReport combines the checks: the highest score wins.
+type SpamReport struct {
+	Score   float64
+	Verdict string
+	Results []SpamCheckResult
+}
+
+type SpamToken struct {
+	Token string
+	Spam  int
+	Ham   int
+}
+
+type SpamStats struct {
+	SpamDocuments int
+	HamDocuments  int
+	Tokens        int
+	TopTokens     []SpamToken
+}
+
+// SpamSample is a moderated comment the classifier can learn from.
+type SpamSample struct {
+	CommentID uuid.UUID
+	Content   string
+	Spam      bool
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -12,6 +14,30 @@ type User struct {
 	Email     string
 	Password  string
 	Salt      string
+	Role      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
 	Posts     []Post
 	Comment   []Comment
+
+	FollowerCount  int
+	FollowingCount int
+	IsFollowing    bool
+}
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// DeletedAuthorName is shown in place of the author of content whose user
+// account has been deleted.
+const DeletedAuthorName = "[deleted]"
+
+// Author is the public summary of a user attached to the content they wrote.
+type Author struct {
+	ID          uuid.UUID
+	Username    string
+	DisplayName string
 }
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvents are the events webhooks can subscribe to.
+var WebhookEvents = []string{EventPostPublished, EventPostUpdated, EventPostDeleted, EventCommentCreated}
+
+// Webhook calls URL when one of Events happens. Users' webhooks only hear
+// about their own posts and the comments on them; global ones, which only
+// admins can create, hear about every post.
+type Webhook struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	URL       string
+	Secret    string
+	Events    []string
+	Global    bool
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+// WebhookDelivery is one event sent, or still to be sent, to a webhook,
+// with the outcome of its last attempt.
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	WebhookID      uuid.UUID
+	Event          string
+	Payload        string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	ResponseStatus int
+	ResponseBody   string
+	Error          string
+	CreatedAt      time.Time
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+type backupRepository struct {
+	DB     *database.DB
+	dir    string
+	policy database.RetentionPolicy
+}
+
+func NewBackupRepository(db *database.DB, dir string, policy database.RetentionPolicy) interfaces.BackupRepository {
+	return &backupRepository{DB: db, dir: dir, policy: policy}
+}
+
+func (r *backupRepository) Create(ctx context.Context) (*models.Backup, error) {
+	backup, err := database.CreateBackup(ctx, r.DB, r.dir, r.policy)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Backup{Name: backup.Name, Size: backup.Size, CreatedAt: backup.CreatedAt}, nil
+}
+
+func (r *backupRepository) GetAll(ctx context.Context) ([]*models.Backup, error) {
+	backups, err := database.ListBackups(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Backup, len(backups))
+	for i, backup := range backups {
+		result[i] = &models.Backup{Name: backup.Name, Size: backup.Size, CreatedAt: backup.CreatedAt}
+	}
+	return result, nil
+}
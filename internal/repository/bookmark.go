@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/google/uuid"
+)
+
+type bookmarkRepository struct {
+	DB *database.DB
+}
+
+func NewBookmarkRepository(db *database.DB) interfaces.BookmarkRepository {
+	return &bookmarkRepository{DB: db}
+}
+
+// Add is a no-op when the post is already bookmarked.
+func (r *bookmarkRepository) Add(ctx context.Context, userID, postID uuid.UUID) error {
+	query := "INSERT INTO bookmarks (user_id, post_id, created_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING"
+	_, err := r.DB.ExecContext(ctx, query, userID, postID, time.Now().UTC())
+	return err
+}
+
+func (r *bookmarkRepository) Remove(ctx context.Context, userID, postID uuid.UUID) error {
+	_, err := r.DB.ExecContext(ctx, "DELETE FROM bookmarks WHERE user_id = ? AND post_id = ?", userID, postID)
+	return err
+}
+
+// GetBookmarked returns which of the posts the user has bookmarked. Posts
+// that aren't bookmarked are absent from the result.
+func (r *bookmarkRepository) GetBookmarked(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	bookmarked := make(map[uuid.UUID]bool)
+	if len(postIDs) == 0 || userID == uuid.Nil {
+		return bookmarked, nil
+	}
+
+	placeholders, args := uuidList(postIDs)
+	query := "SELECT post_id FROM bookmarks WHERE user_id = ? AND post_id IN (" + placeholders + ")"
+	rows, err := r.DB.QueryContext(ctx, query, append([]any{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		bookmarked[id] = true
+	}
+	return bookmarked, rows.Err()
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type coAuthorRepository struct {
+	DB *database.DB
+}
+
+func NewCoAuthorRepository(db *database.DB) interfaces.CoAuthorRepository {
+	return &coAuthorRepository{DB: db}
+}
+
+func (r *coAuthorRepository) Add(ctx context.Context, postID, userID uuid.UUID) error {
+	query := "INSERT INTO post_coauthors (post_id, user_id, created_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING"
+	_, err := r.DB.ExecContext(ctx, query, postID, userID, time.Now().UTC())
+	return err
+}
+
+func (r *coAuthorRepository) Remove(ctx context.Context, postID, userID uuid.UUID) error {
+	_, err := r.DB.ExecContext(ctx, "DELETE FROM post_coauthors WHERE post_id = ? AND user_id = ?", postID, userID)
+	return err
+}
+
+func (r *coAuthorRepository) IsCoAuthor(ctx context.Context, postID, userID uuid.UUID) (bool, error) {
+	var exists int
+	err := r.DB.QueryRowContext(ctx, "SELECT 1 FROM post_coauthors WHERE post_id = ? AND user_id = ?", postID, userID).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetByPostID lists the co-authors of a post in the order they were added.
+func (r *coAuthorRepository) GetByPostID(ctx context.Context, postID uuid.UUID) ([]*models.CoAuthor, error) {
+	query := `SELECT u.id, u.username, u.firstName, u.lastName, c.created_at
+		FROM post_coauthors c JOIN users u ON u.id = c.user_id
+		WHERE c.post_id = ? ORDER BY c.created_at, u.id`
+	rows, err := r.DB.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coAuthors []*models.CoAuthor
+	for rows.Next() {
+		var coAuthor models.CoAuthor
+		var firstName, lastName string
+		if err := rows.Scan(&coAuthor.User.ID, &coAuthor.User.Username, &firstName, &lastName, &coAuthor.CreatedAt); err != nil {
+			return nil, err
+		}
+		coAuthor.User.DisplayName = strings.TrimSpace(firstName + " " + lastName)
+		coAuthors = append(coAuthors, &coAuthor)
+	}
+	return coAuthors, rows.Err()
+}
@@ -1,69 +1,187 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/ahmetilboga2004/go-blog/config/database"
 	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
 	"github.com/ahmetilboga2004/go-blog/internal/models"
 	"github.com/google/uuid"
 )
 
+const (
+	commentSelect = `SELECT c.id, c.content, c.user_id, c.post_id, c.parent_id, c.depth, c.path, c.deleted_at, c.status, c.spam_score, c.approved_at, c.created_at, c.updated_at,
+	u.username, u.firstName, u.lastName
+	FROM comments c LEFT JOIN users u ON u.id = c.user_id`
+
+	commentPathTimeLayout = "20060102150405.000000000"
+)
+
 type CommentRepository struct {
-	DB *sql.DB
+	DB *database.DB
 }
 
-func NewCommentRepository(db *sql.DB) interfaces.CommentRepository {
+func NewCommentRepository(db *database.DB) interfaces.CommentRepository {
 	return &CommentRepository{DB: db}
 }
 
-func (r *CommentRepository) Create(comment *models.Comment) (*models.Comment, error) {
+// Create appends the comment to its parent's thread path. Each path segment
+// is the creation time followed by the id, so sorting by path lists a thread
+// depth first with replies oldest first.
+func (r *CommentRepository) Create(ctx context.Context, comment *models.Comment) (*models.Comment, error) {
 	commentID := uuid.New()
-	query := `INSERT INTO comments (id, content, user_id, post_id) VALUES (?, ?, ?, ?) RETURNING *`
-	row := r.DB.QueryRow(query, commentID, comment.Content, comment.UserID, comment.PostID)
-	if err := row.Scan(&comment.ID, &comment.Content, &comment.UserID, &comment.PostID); err != nil {
+	now := time.Now().UTC()
+	segment := now.Format(commentPathTimeLayout) + "-" + commentID.String() + "/"
+	var approvedAt *time.Time
+	if comment.Status == models.CommentStatusApproved {
+		approvedAt = &now
+	}
+	query := `INSERT INTO comments (id, content, user_id, post_id, parent_id, status, spam_score, approved_at, depth, path, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?,
+			COALESCE((SELECT depth + 1 FROM comments WHERE id = ?), 0),
+			COALESCE((SELECT path FROM comments WHERE id = ?), '') || ?,
+			?, ?)`
+	_, err := r.DB.ExecContext(ctx, query, commentID, comment.Content, comment.UserID, comment.PostID, comment.ParentID, comment.Status, comment.SpamScore, approvedAt,
+		comment.ParentID, comment.ParentID, segment, now, now)
+	if err != nil {
 		return nil, err
 	}
-	return comment, nil
+	return r.GetByID(ctx, commentID)
 }
 
-func (r *CommentRepository) GetAll() ([]*models.Comment, error) {
-	rows, err := r.DB.Query("SELECT * FROM comments")
+var commentSorts = map[string]sortColumn{
+	"createdAt": {column: "c.created_at", isTime: true},
+	"updatedAt": {column: "c.updated_at", isTime: true},
+	"thread":    {column: "c.path"},
+}
+
+func scanComment(row rowScanner) (*models.Comment, error) {
+	var comment models.Comment
+	var deletedAt, approvedAt sql.NullTime
+	var username, firstName, lastName sql.NullString
+	err := row.Scan(&comment.ID, &comment.Content, &comment.UserID, &comment.PostID, &comment.ParentID, &comment.Depth, &comment.Path,
+		&deletedAt, &comment.Status, &comment.SpamScore, &approvedAt, &comment.CreatedAt, &comment.UpdatedAt,
+		&username, &firstName, &lastName)
 	if err != nil {
 		return nil, err
 	}
+	comment.Author = author(comment.UserID, username, firstName, lastName)
+	if approvedAt.Valid {
+		comment.ApprovedAt = &approvedAt.Time
+	}
+	if deletedAt.Valid {
+		comment.Deleted = true
+		comment.Content = models.DeletedCommentContent
+	}
+	return &comment, nil
+}
+
+// GetAll only returns comments on posts the viewer can see. Comments that are
+// not approved are only shown to their author.
+func (r *CommentRepository) GetAll(ctx context.Context, opts models.ListOptions) ([]*models.Comment, string, error) {
+	var q listQuery
+	q.add("(p.status = ? OR p.user_id = ?)", models.PostStatusPublished, opts.ViewerID)
+	q.add("(c.status = ? OR c.user_id = ?)", models.CommentStatusApproved, opts.ViewerID)
+	if opts.AuthorID != uuid.Nil {
+		q.add("c.user_id = ?", opts.AuthorID)
+	}
+	if opts.PostID != uuid.Nil {
+		q.add("c.post_id = ?", opts.PostID)
+	}
+	q.addDateRange("c.created_at", &opts)
+	return r.list(ctx, &q, commentSelect+" JOIN posts p ON p.id = c.post_id", opts)
+}
+
+// GetByStatus lists the comments in a moderation state regardless of who can
+// see them.
+func (r *CommentRepository) GetByStatus(ctx context.Context, status string, opts models.ListOptions) ([]*models.Comment, string, error) {
+	var q listQuery
+	q.add("c.status = ?", status)
+	q.add("c.deleted_at IS NULL")
+	return r.list(ctx, &q, commentSelect, opts)
+}
+
+func (r *CommentRepository) list(ctx context.Context, q *listQuery, base string, opts models.ListOptions) ([]*models.Comment, string, error) {
+	query, sort, err := q.build(base, "c.id", &opts, commentSorts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, "", err
+	}
 	defer rows.Close()
 	var comments []*models.Comment
 	for rows.Next() {
-		var comment models.Comment
-		if err := rows.Scan(&comment.ID, &comment.Content, &comment.UserID, &comment.PostID); err != nil {
-			return nil, err
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, "", err
 		}
-		comments = append(comments, &comment)
+		comments = append(comments, comment)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return comments, nil
+
+	var next string
+	if len(comments) > opts.Limit {
+		comments = comments[:opts.Limit]
+		last := comments[len(comments)-1]
+		next = nextCursor(&opts, sort, commentSortValue(last, opts.Sort), last.ID)
+	}
+	return comments, next, nil
 }
 
-func (r *CommentRepository) GetByID(id uuid.UUID) (*models.Comment, error) {
-	query := `SELECT * FROM comments WHERE id = ?`
-	row := r.DB.QueryRow(query, id)
-	var comment models.Comment
-	if err := row.Scan(&comment.ID, &comment.Content, &comment.UserID, &comment.PostID); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("comment not found")
-		}
+func commentSortValue(comment *models.Comment, sort string) any {
+	switch sort {
+	case "updatedAt":
+		return comment.UpdatedAt
+	case "thread":
+		return comment.Path
+	}
+	return comment.CreatedAt
+}
+
+// CountByPostIDs counts the comments of several posts in one query, leaving
+// out deleted placeholders and comments that are not approved. Posts without comments are absent from the result.
+func (r *CommentRepository) CountByPostIDs(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(postIDs))
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(postIDs)), ", ")
+	args := make([]any, 0, len(postIDs)+1)
+	args = append(args, models.CommentStatusApproved)
+	for _, id := range postIDs {
+		args = append(args, id)
+	}
+	query := "SELECT post_id, COUNT(*) FROM comments WHERE deleted_at IS NULL AND status = ? AND post_id IN (" + placeholders + ") GROUP BY post_id"
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
 		return nil, err
 	}
-	return &comment, nil
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID uuid.UUID
+		var count int
+		if err := rows.Scan(&postID, &count); err != nil {
+			return nil, err
+		}
+		counts[postID] = count
+	}
+	return counts, rows.Err()
 }
 
-func (r *CommentRepository) Update(id uuid.UUID, comment *models.Comment) (*models.Comment, error) {
-	query := "UPDATE comments SET content = ? WHERE id = ? RETURNONG *"
-	row := r.DB.QueryRow(query, comment.Content, id)
-	if err := row.Scan(&comment.ID, &comment.Content, &comment.UserID, &comment.PostID); err != nil {
+func (r *CommentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Comment, error) {
+	comment, err := scanComment(r.DB.QueryRowContext(ctx, commentSelect+" WHERE c.id = ?", id))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("comment not found")
 		}
@@ -72,8 +190,86 @@ func (r *CommentRepository) Update(id uuid.UUID, comment *models.Comment) (*mode
 	return comment, nil
 }
 
-func (r *CommentRepository) Delete(id uuid.UUID) error {
-	result, err := r.DB.Exec("DELETE FROM comments WHERE id = ?", id)
+func (r *CommentRepository) Update(ctx context.Context, id uuid.UUID, comment *models.Comment) (*models.Comment, error) {
+	result, err := r.DB.ExecContext(ctx, "UPDATE comments SET content = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL", comment.Content, time.Now().UTC(), id)
+	if err != nil {
+		return nil, err
+	}
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowAffected == 0 {
+		return nil, errors.New("comment not found")
+	}
+	return r.GetByID(ctx, id)
+}
+
+// HasApproved reports whether the user has at least one approved comment.
+func (r *CommentRepository) HasApproved(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM comments WHERE user_id = ? AND status = ?)", userID, models.CommentStatusApproved).Scan(&exists)
+	return exists, err
+}
+
+// CountByUserSince counts the comments a user wrote in [from, to).
+func (r *CommentRepository) CountByUserSince(ctx context.Context, userID uuid.UUID, from, to time.Time) (int, error) {
+	var count int
+	err := r.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM comments WHERE user_id = ? AND created_at >= ? AND created_at < ?", userID, from.UTC(), to.UTC()).Scan(&count)
+	return count, err
+}
+
+// SetStatus moves the comments to a moderation state and returns how many of
+// them exist. Approving a comment records when it was first approved.
+func (r *CommentRepository) SetStatus(ctx context.Context, ids []uuid.UUID, status string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	var approvedAt *time.Time
+	if status == models.CommentStatusApproved {
+		now := time.Now().UTC()
+		approvedAt = &now
+	}
+	args := make([]any, 0, len(ids)+2)
+	args = append(args, status, approvedAt)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	result, err := r.DB.ExecContext(ctx, "UPDATE comments SET status = ?, approved_at = COALESCE(approved_at, ?) WHERE id IN ("+placeholders+")", args...)
+	if err != nil {
+		return 0, err
+	}
+	rowAffected, err := result.RowsAffected()
+	return int(rowAffected), err
+}
+
+func (r *CommentRepository) HasReplies(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM comments WHERE parent_id = ?)", id).Scan(&exists)
+	return exists, err
+}
+
+// SoftDelete clears the content and author of a comment but keeps the row,
+// so its replies still have a parent.
+func (r *CommentRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	now := time.Now().UTC()
+	result, err := r.DB.ExecContext(ctx, "UPDATE comments SET content = '', user_id = NULL, deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL", now, now, id)
+	if err != nil {
+		return err
+	}
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowAffected == 0 {
+		return errors.New("comment not found")
+	}
+	return nil
+}
+
+func (r *CommentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM comments WHERE id = ?", id)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCommentRepositoryThreads(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		repo := NewCommentRepository(db)
+		alice := createUser(t, db, "alice")
+		bob := createUser(t, db, "bob")
+		post := createPost(t, db, alice.ID, "A post", models.PostStatusPublished)
+
+		first := createComment(t, db, post.ID, bob.ID, nil, "first")
+		second := createComment(t, db, post.ID, alice.ID, nil, "second")
+		reply := createComment(t, db, post.ID, alice.ID, &first.ID, "reply")
+		nested := createComment(t, db, post.ID, bob.ID, &reply.ID, "nested")
+		if reply.Depth != 1 || nested.Depth != 2 {
+			t.Errorf("depths = %d, %d, want 1, 2", reply.Depth, nested.Depth)
+		}
+		if nested.ParentID == nil || *nested.ParentID != reply.ID {
+			t.Errorf("parent of nested = %v", nested.ParentID)
+		}
+
+		comments, _, err := repo.GetAll(ctx, models.ListOptions{PostID: post.ID, Sort: "thread"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []uuid.UUID{first.ID, reply.ID, nested.ID, second.ID}
+		if len(comments) != len(want) {
+			t.Fatalf("got %d comments, want %d", len(comments), len(want))
+		}
+		for i, comment := range comments {
+			if comment.ID != want[i] {
+				t.Errorf("comment %d = %q, want thread order first, reply, nested, second", i, comment.Content)
+			}
+		}
+		if author := comments[0].Author; author.ID != bob.ID || author.Username != "bob" || author.DisplayName != "Bob Doe" {
+			t.Errorf("author of first = %+v, want bob", author)
+		}
+
+		if hasReplies, err := repo.HasReplies(ctx, first.ID); err != nil || !hasReplies {
+			t.Errorf("HasReplies(first) = %v, %v", hasReplies, err)
+		}
+		if err := repo.SoftDelete(ctx, first.ID); err != nil {
+			t.Fatal(err)
+		}
+		deleted, err := repo.GetByID(ctx, first.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !deleted.Deleted || deleted.Content != models.DeletedCommentContent || deleted.UserID != uuid.Nil {
+			t.Errorf("soft deleted comment = %+v", deleted)
+		}
+		if deleted.Author.DisplayName != models.DeletedAuthorName {
+			t.Errorf("author of soft deleted comment = %+v", deleted.Author)
+		}
+
+		counts, err := repo.CountByPostIDs(ctx, []uuid.UUID{post.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if counts[post.ID] != 3 {
+			t.Errorf("comment count = %d, want 3 without the deleted one", counts[post.ID])
+		}
+	})
+}
+
+func TestCommentRepositoryModeration(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		repo := NewCommentRepository(db)
+		alice := createUser(t, db, "alice")
+		bob := createUser(t, db, "bob")
+		post := createPost(t, db, alice.ID, "A post", models.PostStatusPublished)
+
+		pending, err := repo.Create(ctx, &models.Comment{Content: "held", PostID: post.ID, UserID: bob.ID, Status: models.CommentStatusPending})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		visible := func(viewer uuid.UUID) int {
+			comments, _, err := repo.GetAll(ctx, models.ListOptions{PostID: post.ID, ViewerID: viewer})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return len(comments)
+		}
+		if visible(alice.ID) != 0 || visible(bob.ID) != 1 {
+			t.Error("pending comments must only be visible to their author")
+		}
+
+		queue, _, err := repo.GetByStatus(ctx, models.CommentStatusPending, models.ListOptions{})
+		if err != nil || len(queue) != 1 {
+			t.Fatalf("moderation queue = %d comments, %v", len(queue), err)
+		}
+		updated, err := repo.SetStatus(ctx, []uuid.UUID{pending.ID}, models.CommentStatusApproved)
+		if err != nil || updated != 1 {
+			t.Fatalf("SetStatus = %d, %v", updated, err)
+		}
+		if visible(alice.ID) != 1 {
+			t.Error("approved comment is not visible")
+		}
+		if approved, err := repo.HasApproved(ctx, bob.ID); err != nil || !approved {
+			t.Errorf("HasApproved = %v, %v", approved, err)
+		}
+
+		// The first approval is kept when the comment is rejected and
+		// approved again, so it isn't announced twice.
+		first, err := repo.GetByID(ctx, pending.ID)
+		if err != nil || first.ApprovedAt == nil {
+			t.Fatalf("approved comment has no approval time: %v", err)
+		}
+		for _, status := range []string{models.CommentStatusRejected, models.CommentStatusApproved} {
+			if _, err := repo.SetStatus(ctx, []uuid.UUID{pending.ID}, status); err != nil {
+				t.Fatal(err)
+			}
+		}
+		again, err := repo.GetByID(ctx, pending.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.ApprovedAt == nil || !again.ApprovedAt.Equal(*first.ApprovedAt) {
+			t.Errorf("approval time = %v, want %v", again.ApprovedAt, first.ApprovedAt)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestDeletePostCascades(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		alice := createUser(t, db, "alice")
+		bob := createUser(t, db, "bob")
+		post := createPost(t, db, alice.ID, "Doomed", models.PostStatusPublished, "go")
+		kept := createPost(t, db, alice.ID, "Kept", models.PostStatusPublished)
+		comment := createComment(t, db, post.ID, bob.ID, nil, "first")
+		reply := createComment(t, db, post.ID, alice.ID, &comment.ID, "reply")
+		createComment(t, db, kept.ID, bob.ID, nil, "elsewhere")
+
+		reactions := NewReactionRepository(db)
+		if err := reactions.Add(ctx, models.ReactionTargetPost, post.ID, bob.ID, models.ReactionLike); err != nil {
+			t.Fatal(err)
+		}
+		if err := reactions.Add(ctx, models.ReactionTargetComment, reply.ID, bob.ID, models.ReactionLike); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewBookmarkRepository(db).Add(ctx, bob.ID, post.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := NewPostRepository(db).Delete(ctx, post.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, table := range []string{"comments", "post_reactions", "post_reaction_counts", "bookmarks", "post_tags"} {
+			if n := count(t, db, table, "post_id = ?", post.ID); n != 0 {
+				t.Errorf("%s: %d rows left for the deleted post", table, n)
+			}
+		}
+		for _, table := range []string{"comment_reactions", "comment_reaction_counts"} {
+			if n := count(t, db, table, "comment_id IN (?, ?)", comment.ID, reply.ID); n != 0 {
+				t.Errorf("%s: %d rows left for the deleted comments", table, n)
+			}
+		}
+		if n := count(t, db, "comments", "post_id = ?", kept.ID); n != 1 {
+			t.Errorf("comments on the other post = %d, want 1", n)
+		}
+	})
+}
+
+func TestDeleteUserAnonymizesContent(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		alice := createUser(t, db, "alice")
+		bob := createUser(t, db, "bob")
+		alicePost := createPost(t, db, alice.ID, "By Alice", models.PostStatusPublished)
+		bobPost := createPost(t, db, bob.ID, "By Bob", models.PostStatusPublished)
+		aliceComment := createComment(t, db, bobPost.ID, alice.ID, nil, "from alice")
+		bobComment := createComment(t, db, alicePost.ID, bob.ID, nil, "from bob")
+
+		reactions := NewReactionRepository(db)
+		if err := reactions.Add(ctx, models.ReactionTargetPost, bobPost.ID, alice.ID, models.ReactionLike); err != nil {
+			t.Fatal(err)
+		}
+		if err := reactions.Add(ctx, models.ReactionTargetComment, bobComment.ID, alice.ID, models.ReactionLike); err != nil {
+			t.Fatal(err)
+		}
+		if err := reactions.Add(ctx, models.ReactionTargetPost, alicePost.ID, bob.ID, models.ReactionLike); err != nil {
+			t.Fatal(err)
+		}
+		follows := NewFollowRepository(db)
+		if _, err := follows.Follow(ctx, alice.ID, bob.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := follows.Follow(ctx, bob.ID, alice.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewBookmarkRepository(db).Add(ctx, alice.ID, bobPost.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := NewUserRepository(db).Delete(ctx, alice.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if n := count(t, db, "posts", "id = ? AND user_id IS NULL", alicePost.ID); n != 1 {
+			t.Errorf("anonymized posts = %d, want 1", n)
+		}
+		if n := count(t, db, "comments", "id = ? AND user_id IS NULL", aliceComment.ID); n != 1 {
+			t.Errorf("anonymized comments = %d, want 1", n)
+		}
+		post, err := NewPostRepository(db).GetByID(ctx, alicePost.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if post.Author.DisplayName != models.DeletedAuthorName {
+			t.Errorf("author = %q, want %q", post.Author.DisplayName, models.DeletedAuthorName)
+		}
+
+		for _, table := range []string{"post_reactions", "comment_reactions", "bookmarks"} {
+			if n := count(t, db, table, "user_id = ?", alice.ID); n != 0 {
+				t.Errorf("%s: %d rows left for the deleted user", table, n)
+			}
+		}
+		if n := count(t, db, "follows", "follower_id = ? OR followee_id = ?", alice.ID, alice.ID); n != 0 {
+			t.Errorf("follows: %d rows left for the deleted user", n)
+		}
+		if n := count(t, db, "post_reaction_counts", "post_id = ?", bobPost.ID); n != 0 {
+			t.Errorf("reaction counts on bob's post = %d rows, want 0", n)
+		}
+		if n := count(t, db, "post_reactions", "post_id = ?", alicePost.ID); n != 1 {
+			t.Errorf("reactions on the anonymized post = %d, want 1", n)
+		}
+	})
+}
+
+func TestDeletedAuthorContentStaysHidden(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		alice := createUser(t, db, "alice")
+		bob := createUser(t, db, "bob")
+		published := createPost(t, db, bob.ID, "Published", models.PostStatusPublished)
+		draft := createPost(t, db, alice.ID, "Draft", models.PostStatusDraft)
+		pending, err := NewCommentRepository(db).Create(ctx, &models.Comment{
+			Content: "waiting",
+			PostID:  published.ID,
+			UserID:  alice.ID,
+			Status:  models.CommentStatusPending,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := NewUserRepository(db).Delete(ctx, alice.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		post, err := NewPostRepository(db).GetByID(ctx, draft.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if post.VisibleTo(uuid.Nil) {
+			t.Error("anonymous viewer sees the draft of a deleted user")
+		}
+		if post.VisibleTo(bob.ID) {
+			t.Error("another user sees the draft of a deleted user")
+		}
+		comment, err := NewCommentRepository(db).GetByID(ctx, pending.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if comment.VisibleTo(uuid.Nil) {
+			t.Error("anonymous viewer sees the pending comment of a deleted user")
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type followRepository struct {
+	DB *database.DB
+}
+
+func NewFollowRepository(db *database.DB) interfaces.FollowRepository {
+	return &followRepository{DB: db}
+}
+
+// Follow reports whether a new follow was created; following someone twice
+// is a no-op.
+func (r *followRepository) Follow(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error) {
+	query := "INSERT INTO follows (follower_id, followee_id, created_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING"
+	result, err := r.DB.ExecContext(ctx, query, followerID, followeeID, time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+	created, err := result.RowsAffected()
+	return created > 0, err
+}
+
+func (r *followRepository) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	_, err := r.DB.ExecContext(ctx, "DELETE FROM follows WHERE follower_id = ? AND followee_id = ?", followerID, followeeID)
+	return err
+}
+
+func (r *followRepository) IsFollowing(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error) {
+	var exists int
+	err := r.DB.QueryRowContext(ctx, "SELECT 1 FROM follows WHERE follower_id = ? AND followee_id = ?", followerID, followeeID).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *followRepository) GetCounts(ctx context.Context, userID uuid.UUID) (int, int, error) {
+	var followers, following int
+	query := `SELECT (SELECT COUNT(*) FROM follows WHERE followee_id = ?),
+		(SELECT COUNT(*) FROM follows WHERE follower_id = ?)`
+	if err := r.DB.QueryRowContext(ctx, query, userID, userID).Scan(&followers, &following); err != nil {
+		return 0, 0, err
+	}
+	return followers, following, nil
+}
+
+func (r *followRepository) GetFollowerIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.DB.QueryContext(ctx, "SELECT follower_id FROM follows WHERE followee_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+var followSorts = map[string]sortColumn{
+	"createdAt": {column: "f.created_at", isTime: true},
+}
+
+// GetFollowers lists the users following userID, most recent follow first.
+func (r *followRepository) GetFollowers(ctx context.Context, userID uuid.UUID, opts models.ListOptions) ([]*models.Follow, string, error) {
+	return r.list(ctx, "f.follower_id", "f.followee_id", userID, opts)
+}
+
+// GetFollowing lists the users userID follows, most recent follow first.
+func (r *followRepository) GetFollowing(ctx context.Context, userID uuid.UUID, opts models.ListOptions) ([]*models.Follow, string, error) {
+	return r.list(ctx, "f.followee_id", "f.follower_id", userID, opts)
+}
+
+// list returns the users in column of the follows whose other column is
+// userID.
+func (r *followRepository) list(ctx context.Context, column, filter string, userID uuid.UUID, opts models.ListOptions) ([]*models.Follow, string, error) {
+	var q listQuery
+	q.add(filter+" = ?", userID)
+	base := "SELECT u.id, u.username, u.firstName, u.lastName, f.created_at FROM follows f JOIN users u ON u.id = " + column
+	query, sort, err := q.build(base, "u.id", &opts, followSorts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var follows []*models.Follow
+	for rows.Next() {
+		var follow models.Follow
+		var firstName, lastName string
+		if err := rows.Scan(&follow.User.ID, &follow.User.Username, &firstName, &lastName, &follow.CreatedAt); err != nil {
+			return nil, "", err
+		}
+		follow.User.DisplayName = strings.TrimSpace(firstName + " " + lastName)
+		follows = append(follows, &follow)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(follows) > opts.Limit {
+		follows = follows[:opts.Limit]
+		last := follows[len(follows)-1]
+		next = nextCursor(&opts, sort, last.CreatedAt, last.User.ID)
+	}
+	return follows, next, nil
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+const notificationSelect = `SELECT n.id, n.user_id, n.type, n.actor_id, n.post_id, n.comment_id, n.read_at, n.created_at,
+	u.username, u.firstName, u.lastName, p.title
+	FROM notifications n
+	LEFT JOIN users u ON u.id = n.actor_id
+	LEFT JOIN posts p ON p.id = n.post_id`
+
+type notificationRepository struct {
+	DB *database.DB
+}
+
+func NewNotificationRepository(db *database.DB) interfaces.NotificationRepository {
+	return &notificationRepository{DB: db}
+}
+
+func scanNotification(row rowScanner) (*models.Notification, error) {
+	var n models.Notification
+	var actorID, postID, commentID uuid.NullUUID
+	var readAt sql.NullTime
+	var username, firstName, lastName, title sql.NullString
+	err := row.Scan(&n.ID, &n.UserID, &n.Type, &actorID, &postID, &commentID, &readAt, &n.CreatedAt,
+		&username, &firstName, &lastName, &title)
+	if err != nil {
+		return nil, err
+	}
+	if actorID.Valid {
+		n.ActorID = &actorID.UUID
+		n.Actor = models.Author{
+			ID:          actorID.UUID,
+			Username:    username.String,
+			DisplayName: strings.TrimSpace(firstName.String + " " + lastName.String),
+		}
+	} else {
+		n.Actor = models.Author{DisplayName: models.DeletedAuthorName}
+	}
+	if postID.Valid {
+		n.PostID = &postID.UUID
+	}
+	if commentID.Valid {
+		n.CommentID = &commentID.UUID
+	}
+	if readAt.Valid {
+		n.ReadAt = &readAt.Time
+	}
+	n.PostTitle = title.String
+	return &n, nil
+}
+
+// Create fills in the ID and creation time of the notification.
+func (r *notificationRepository) Create(ctx context.Context, n *models.Notification) error {
+	id, now := uuid.New(), time.Now().UTC()
+	query := "INSERT INTO notifications (id, user_id, type, actor_id, post_id, comment_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	if _, err := r.DB.ExecContext(ctx, query, id, n.UserID, n.Type, n.ActorID, n.PostID, n.CommentID, now); err != nil {
+		return err
+	}
+	n.ID, n.CreatedAt = id, now
+	return nil
+}
+
+// FilterEnabled returns the users who haven't turned notificationType off.
+func (r *notificationRepository) FilterEnabled(ctx context.Context, notificationType string, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	placeholders, args := uuidList(userIDs)
+	query := "SELECT user_id FROM notification_preferences WHERE type = ? AND enabled = ? AND user_id IN (" + placeholders + ")"
+	rows, err := r.DB.QueryContext(ctx, query, append([]any{notificationType, false}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	disabled := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		disabled[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var enabled []uuid.UUID
+	for _, id := range userIDs {
+		if !disabled[id] {
+			enabled = append(enabled, id)
+		}
+	}
+	return enabled, nil
+}
+
+var notificationSorts = map[string]sortColumn{
+	"createdAt": {column: "n.created_at", isTime: true},
+}
+
+// GetByUserID lists the user's notifications, newest first.
+func (r *notificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, opts models.ListOptions) ([]*models.Notification, string, error) {
+	var q listQuery
+	q.add("n.user_id = ?", userID)
+	if unreadOnly {
+		q.add("n.read_at IS NULL")
+	}
+	opts.Sort = "createdAt"
+	opts.Desc = true
+	query, sort, err := q.build(notificationSelect, "n.id", &opts, notificationSorts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		notifications = append(notifications, n)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(notifications) > opts.Limit {
+		notifications = notifications[:opts.Limit]
+		last := notifications[len(notifications)-1]
+		next = nextCursor(&opts, sort, last.CreatedAt, last.ID)
+	}
+	return notifications, next, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL", userID).Scan(&count)
+	return count, err
+}
+
+// MarkRead keeps the time a notification was first read.
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	query := "UPDATE notifications SET read_at = COALESCE(read_at, ?) WHERE id = ? AND user_id = ?"
+	result, err := r.DB.ExecContext(ctx, query, time.Now().UTC(), id, userID)
+	if err != nil {
+		return err
+	}
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowAffected == 0 {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	result, err := r.DB.ExecContext(ctx, "UPDATE notifications SET read_at = ? WHERE user_id = ? AND read_at IS NULL", time.Now().UTC(), userID)
+	if err != nil {
+		return 0, err
+	}
+	updated, err := result.RowsAffected()
+	return int(updated), err
+}
+
+// GetPreferences returns the types the user set explicitly.
+func (r *notificationRepository) GetPreferences(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	rows, err := r.DB.QueryContext(ctx, "SELECT type, enabled FROM notification_preferences WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	preferences := make(map[string]bool)
+	for rows.Next() {
+		var notificationType string
+		var enabled bool
+		if err := rows.Scan(&notificationType, &enabled); err != nil {
+			return nil, err
+		}
+		preferences[notificationType] = enabled
+	}
+	return preferences, rows.Err()
+}
+
+func (r *notificationRepository) SetPreference(ctx context.Context, userID uuid.UUID, notificationType string, enabled bool) error {
+	query := `INSERT INTO notification_preferences (user_id, type, enabled) VALUES (?, ?, ?)
+		ON CONFLICT (user_id, type) DO UPDATE SET enabled = excluded.enabled`
+	_, err := r.DB.ExecContext(ctx, query, userID, notificationType, enabled)
+	return err
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+	defaultSort      = "createdAt"
+)
+
+type sortColumn struct {
+	column string
+	isTime bool
+}
+
+type cursor struct {
+	Sort  string    `json:"s"`
+	Desc  bool      `json:"d"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+type listQuery struct {
+	where []string
+	args  []any
+}
+
+func (q *listQuery) add(condition string, args ...any) {
+	q.where = append(q.where, condition)
+	q.args = append(q.args, args...)
+}
+
+// build appends the keyset condition, ordering and limit to the base query.
+// One extra row is fetched so the caller can tell whether a next page exists.
+func (q *listQuery) build(base, idColumn string, opts *models.ListOptions, sorts map[string]sortColumn) (string, sortColumn, error) {
+	if opts.Sort == "" {
+		opts.Sort = defaultSort
+		opts.Desc = true
+	}
+	sort, ok := sorts[opts.Sort]
+	if !ok {
+		return "", sortColumn{}, fmt.Errorf("invalid sort field: %s", opts.Sort)
+	}
+	normalizeLimit(opts)
+
+	if opts.Cursor != "" {
+		c, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return "", sortColumn{}, err
+		}
+		if c.Sort != opts.Sort || c.Desc != opts.Desc {
+			return "", sortColumn{}, errors.New("cursor does not match sort order")
+		}
+		var value any = c.Value
+		if sort.isTime {
+			t, err := time.Parse(time.RFC3339Nano, c.Value)
+			if err != nil {
+				return "", sortColumn{}, models.ErrInvalidCursor
+			}
+			value = t
+		}
+		op := ">"
+		if opts.Desc {
+			op = "<"
+		}
+		q.add(fmt.Sprintf("(%s, %s) %s (?, ?)", sort.column, idColumn, op), value, c.ID)
+	}
+
+	query := base
+	if len(q.where) > 0 {
+		query += " WHERE " + strings.Join(q.where, " AND ")
+	}
+	direction := "ASC"
+	if opts.Desc {
+		direction = "DESC"
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT ?", sort.column, direction, idColumn, direction)
+	q.args = append(q.args, opts.Limit+1)
+	return query, sort, nil
+}
+
+func normalizeLimit(opts *models.ListOptions) {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultPageLimit
+	}
+	if opts.Limit > maxPageLimit {
+		opts.Limit = maxPageLimit
+	}
+}
+
+func (q *listQuery) addDateRange(column string, opts *models.ListOptions) {
+	if !opts.From.IsZero() {
+		q.add(column+" >= ?", opts.From.UTC())
+	}
+	if !opts.To.IsZero() {
+		q.add(column+" < ?", opts.To.UTC())
+	}
+}
+
+func nextCursor(opts *models.ListOptions, sort sortColumn, value any, id uuid.UUID) string {
+	c := cursor{Sort: opts.Sort, Desc: opts.Desc, ID: id}
+	if sort.isTime {
+		c.Value = value.(time.Time).UTC().Format(time.RFC3339Nano)
+	} else {
+		c.Value = fmt.Sprint(value)
+	}
+	return encodeCursor(c)
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Ranked results have no stable sort key, so their cursor is a plain offset.
+func encodeOffsetCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeOffsetCursor(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, models.ErrInvalidCursor
+	}
+	offset, err := strconv.Atoi(string(data))
+	if err != nil || offset < 0 {
+		return 0, models.ErrInvalidCursor
+	}
+	return offset, nil
+}
@@ -1,81 +1,259 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/ahmetilboga2004/go-blog/config/database"
 	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
 	"github.com/ahmetilboga2004/go-blog/internal/models"
 	"github.com/google/uuid"
 )
 
+const postSelect = `SELECT p.id, p.title, p.content, p.content_html, p.status, p.user_id, p.comment_policy, p.comments_closed, p.created_at, p.updated_at,
+	u.username, u.firstName, u.lastName
+	FROM posts p LEFT JOIN users u ON u.id = p.user_id`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
 type postRepository struct {
-	DB *sql.DB
+	DB *database.DB
 }
 
-func NewPostRepository(db *sql.DB) interfaces.PostRepository {
+func NewPostRepository(db *database.DB) interfaces.PostRepository {
 	return &postRepository{DB: db}
 }
 
-func (r *postRepository) Create(post *models.Post) (*models.Post, error) {
-	postID := uuid.New()
-	query := "INSERT INTO posts (id, title, content, user_id) VALUES (?, ?, ?, ?) RETURNING *"
-	err := r.DB.QueryRow(query, postID, post.Title, post.Content, post.UserID).Scan(&post.ID, &post.Title, &post.Content, &post.UserID)
+func scanPost(row rowScanner) (*models.Post, error) {
+	var post models.Post
+	var username, firstName, lastName sql.NullString
+	err := row.Scan(&post.ID, &post.Title, &post.Content, &post.ContentHTML, &post.Status, &post.UserID, &post.CommentPolicy, &post.CommentsClosed,
+		&post.CreatedAt, &post.UpdatedAt,
+		&username, &firstName, &lastName)
 	if err != nil {
 		return nil, err
 	}
-	return post, nil
+	post.Author = author(post.UserID, username, firstName, lastName)
+	return &post, nil
 }
 
-func (r *postRepository) GetAll() ([]*models.Post, error) {
-	rows, err := r.DB.Query("SELECT * FROM posts")
-	if err != nil {
+// author builds the summary of a user joined to their content. The columns
+// are NULL once the user was deleted.
+func author(userID uuid.UUID, username, firstName, lastName sql.NullString) models.Author {
+	if !username.Valid {
+		return models.Author{DisplayName: models.DeletedAuthorName}
+	}
+	return models.Author{
+		ID:          userID,
+		Username:    username.String,
+		DisplayName: strings.TrimSpace(firstName.String + " " + lastName.String),
+	}
+}
+
+func (r *postRepository) Create(ctx context.Context, post *models.Post) (*models.Post, error) {
+	postID := uuid.New()
+	now := time.Now().UTC()
+	query := "INSERT INTO posts (id, title, content, content_html, status, user_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := r.DB.ExecContext(ctx, query, postID, post.Title, post.Content, post.ContentHTML, post.Status, post.UserID, now, now); err != nil {
+		return nil, err
+	}
+	if err := r.setTags(ctx, postID, post.Tags); err != nil {
 		return nil, err
 	}
+	return r.GetByID(ctx, postID)
+}
+
+// setTags replaces the tags of a post. Call it within a transaction.
+func (r *postRepository) setTags(ctx context.Context, postID uuid.UUID, tags []string) error {
+	if _, err := r.DB.ExecContext(ctx, "DELETE FROM post_tags WHERE post_id = ?", postID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if _, err := r.DB.ExecContext(ctx, "INSERT INTO post_tags (post_id, tag) VALUES (?, ?)", postID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachTags loads the tags of a page of posts in one query.
+func (r *postRepository) attachTags(ctx context.Context, posts []*models.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Post, len(posts))
+	ids := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		byID[post.ID] = post
+		ids[i] = post.ID
+		post.Tags = []string{}
+	}
+	placeholders, args := uuidList(ids)
+	rows, err := r.DB.QueryContext(ctx, "SELECT post_id, tag FROM post_tags WHERE post_id IN ("+placeholders+") ORDER BY tag", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID uuid.UUID
+		var tag string
+		if err := rows.Scan(&postID, &tag); err != nil {
+			return err
+		}
+		byID[postID].Tags = append(byID[postID].Tags, tag)
+	}
+	return rows.Err()
+}
+
+var postSorts = map[string]sortColumn{
+	"createdAt": {column: "p.created_at", isTime: true},
+	"updatedAt": {column: "p.updated_at", isTime: true},
+	"title":     {column: "p.title"},
+}
+
+func (r *postRepository) GetAll(ctx context.Context, opts models.ListOptions) ([]*models.Post, string, error) {
+	var q listQuery
+	q.add("(p.status = ? OR p.user_id = ?)", models.PostStatusPublished, opts.ViewerID)
+	if opts.AuthorID != uuid.Nil {
+		q.add("p.user_id = ?", opts.AuthorID)
+	}
+	if opts.BookmarkedBy != uuid.Nil {
+		q.add("p.id IN (SELECT post_id FROM bookmarks WHERE user_id = ?)", opts.BookmarkedBy)
+	}
+	if opts.ListID != uuid.Nil {
+		q.add("p.id IN (SELECT post_id FROM reading_list_posts WHERE list_id = ?)", opts.ListID)
+	}
+	if opts.Tag != "" {
+		q.add("p.id IN (SELECT post_id FROM post_tags WHERE tag = ?)", opts.Tag)
+	}
+	q.addDateRange("p.created_at", &opts)
+	return r.list(ctx, &q, opts, postSorts)
+}
+
+var feedSorts = map[string]sortColumn{
+	"createdAt": postSorts["createdAt"],
+}
+
+// GetFeed returns the published posts of the authors userID follows, newest
+// first. Walking posts_status_created_idx and checking each author against
+// the follows primary key keeps a page cheap however many authors are
+// followed.
+func (r *postRepository) GetFeed(ctx context.Context, userID uuid.UUID, opts models.ListOptions) ([]*models.Post, string, error) {
+	var q listQuery
+	q.add("p.status = ?", models.PostStatusPublished)
+	q.add("p.user_id IN (SELECT followee_id FROM follows WHERE follower_id = ?)", userID)
+	opts.Sort = "createdAt"
+	opts.Desc = true
+	return r.list(ctx, &q, opts, feedSorts)
+}
+
+func (r *postRepository) list(ctx context.Context, q *listQuery, opts models.ListOptions, sorts map[string]sortColumn) ([]*models.Post, string, error) {
+	query, sort, err := q.build(postSelect, "p.id", &opts, sorts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, "", err
+	}
 	defer rows.Close()
 
 	var posts []*models.Post
 	for rows.Next() {
-		var post models.Post
-		if err := rows.Scan(&post.ID, &post.Title, &post.Content, &post.UserID); err != nil {
-			return nil, err
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, "", err
 		}
-		posts = append(posts, &post)
+		posts = append(posts, post)
 	}
 	if err = rows.Err(); err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	var next string
+	if len(posts) > opts.Limit {
+		posts = posts[:opts.Limit]
+		last := posts[len(posts)-1]
+		next = nextCursor(&opts, sort, postSortValue(last, opts.Sort), last.ID)
+	}
+	if err := r.attachTags(ctx, posts); err != nil {
+		return nil, "", err
 	}
-	return posts, nil
+	return posts, next, nil
 }
 
-func (r *postRepository) GetByID(id uuid.UUID) (*models.Post, error) {
-	query := `SELECT * FROM posts WHERE id = ?`
-	row := r.DB.QueryRow(query, id)
-	var post models.Post
-	if err := row.Scan(&post.ID, &post.Title, &post.Content, &post.UserID); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("post not found")
-		}
-		return nil, err
+func postSortValue(post *models.Post, sort string) any {
+	switch sort {
+	case "title":
+		return post.Title
+	case "updatedAt":
+		return post.UpdatedAt
 	}
-	return &post, nil
+	return post.CreatedAt
 }
 
-func (r *postRepository) Update(id uuid.UUID, post *models.Post) (*models.Post, error) {
-	query := `UPDATE posts SET title = ?, content = ? WHERE id = ? RETURNING *`
-	row := r.DB.QueryRow(query, post.Title, post.Content, id)
-	if err := row.Scan(&post.ID, &post.Title, &post.Content, &post.UserID); err != nil {
+func (r *postRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Post, error) {
+	row := r.DB.QueryRowContext(ctx, postSelect+" WHERE p.id = ?", id)
+	post, err := scanPost(row)
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("post not found")
 		}
 		return nil, err
 	}
+	if err := r.attachTags(ctx, []*models.Post{post}); err != nil {
+		return nil, err
+	}
 	return post, nil
 }
 
-func (r *postRepository) Delete(id uuid.UUID) error {
+// Update leaves the tags as they are when post.Tags is nil.
+func (r *postRepository) Update(ctx context.Context, id uuid.UUID, post *models.Post) (*models.Post, error) {
+	query := `UPDATE posts SET title = ?, content = ?, content_html = ?, status = ?, updated_at = ? WHERE id = ?`
+	result, err := r.DB.ExecContext(ctx, query, post.Title, post.Content, post.ContentHTML, post.Status, time.Now().UTC(), id)
+	if err != nil {
+		return nil, err
+	}
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowAffected == 0 {
+		return nil, errors.New("post not found")
+	}
+	if post.Tags != nil {
+		if err := r.setTags(ctx, id, post.Tags); err != nil {
+			return nil, err
+		}
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *postRepository) UpdateCommentSettings(ctx context.Context, id uuid.UUID, policy string, closed bool) error {
+	result, err := r.DB.ExecContext(ctx, "UPDATE posts SET comment_policy = ?, comments_closed = ? WHERE id = ?", policy, closed, id)
+	if err != nil {
+		return err
+	}
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowAffected == 0 {
+		return errors.New("post not found")
+	}
+	return nil
+}
+
+func (r *postRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM posts WHERE id = ?`
-	result, err := r.DB.Exec(query, id)
+	result, err := r.DB.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
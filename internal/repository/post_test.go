@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestPostRepository(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		repo := NewPostRepository(db)
+		alice := createUser(t, db, "alice")
+
+		post := createPost(t, db, alice.ID, "First post", models.PostStatusPublished, "go", "sql")
+		if post.Author.Username != "alice" || post.Author.DisplayName != "Alice Doe" {
+			t.Errorf("author = %+v", post.Author)
+		}
+		if !slices.Equal(post.Tags, []string{"go", "sql"}) {
+			t.Errorf("tags = %v", post.Tags)
+		}
+
+		post.Title = "First post, edited"
+		post.Tags = nil
+		updated, err := repo.Update(ctx, post.ID, post)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated.Title != "First post, edited" || !slices.Equal(updated.Tags, []string{"go", "sql"}) {
+			t.Errorf("after an update without tags: %q %v", updated.Title, updated.Tags)
+		}
+		post.Tags = []string{"postgres"}
+		if updated, err = repo.Update(ctx, post.ID, post); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(updated.Tags, []string{"postgres"}) {
+			t.Errorf("tags after replacing them = %v", updated.Tags)
+		}
+
+		if err := repo.UpdateCommentSettings(ctx, post.ID, models.CommentPolicyModerate, true); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := repo.GetByID(ctx, post.ID); got.CommentPolicy != models.CommentPolicyModerate || !got.CommentsClosed {
+			t.Errorf("comment settings = %q %v", got.CommentPolicy, got.CommentsClosed)
+		}
+
+		if err := repo.Delete(ctx, post.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.GetByID(ctx, post.ID); err == nil {
+			t.Error("deleted post is still found")
+		}
+		if _, err := repo.Update(ctx, post.ID, post); err == nil {
+			t.Error("updating a deleted post succeeded")
+		}
+	})
+}
+
+func TestPostRepositoryGetAll(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		repo := NewPostRepository(db)
+		alice := createUser(t, db, "alice")
+		bob := createUser(t, db, "bob")
+
+		var published []uuid.UUID
+		for _, title := range []string{"Post one", "Post two", "Post three", "Post four", "Post five"} {
+			published = append(published, createPost(t, db, alice.ID, title, models.PostStatusPublished, "go").ID)
+		}
+		createPost(t, db, bob.ID, "Bob's post", models.PostStatusPublished)
+		draft := createPost(t, db, alice.ID, "Alice's draft", models.PostStatusDraft, "go")
+
+		// Walk alice's published posts two at a time, oldest first.
+		var seen []uuid.UUID
+		opts := models.ListOptions{Limit: 2, Sort: "createdAt", AuthorID: alice.ID}
+		for page := 0; ; page++ {
+			posts, next, err := repo.GetAll(ctx, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, post := range posts {
+				seen = append(seen, post.ID)
+			}
+			if next == "" {
+				break
+			}
+			if page > 5 {
+				t.Fatal("pagination does not end")
+			}
+			opts.Cursor = next
+		}
+		if !slices.Equal(seen, published) {
+			t.Errorf("paged posts = %v, want %v", seen, published)
+		}
+
+		posts, _, err := repo.GetAll(ctx, models.ListOptions{Tag: "go", ViewerID: bob.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(posts) != 5 {
+			t.Errorf("bob sees %d posts tagged go, want 5", len(posts))
+		}
+		posts, _, err = repo.GetAll(ctx, models.ListOptions{Tag: "go", ViewerID: alice.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(posts) != 6 || posts[0].ID != draft.ID {
+			t.Errorf("alice sees %d posts tagged go, want 6 starting with her draft", len(posts))
+		}
+
+		if _, _, err := repo.GetAll(ctx, models.ListOptions{Cursor: "not a cursor"}); err == nil {
+			t.Error("an invalid cursor was accepted")
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type reactionTable struct {
+	reactions string
+	counts    string
+	column    string
+}
+
+var reactionTables = map[string]reactionTable{
+	models.ReactionTargetPost:    {reactions: "post_reactions", counts: "post_reaction_counts", column: "post_id"},
+	models.ReactionTargetComment: {reactions: "comment_reactions", counts: "comment_reaction_counts", column: "comment_id"},
+}
+
+type reactionRepository struct {
+	DB *database.DB
+}
+
+func NewReactionRepository(db *database.DB) interfaces.ReactionRepository {
+	return &reactionRepository{DB: db}
+}
+
+func lookupReactionTable(target string) (reactionTable, error) {
+	table, ok := reactionTables[target]
+	if !ok {
+		return reactionTable{}, fmt.Errorf("invalid reaction target: %s", target)
+	}
+	return table, nil
+}
+
+// Add is a no-op when the user already left this reaction. The counters are
+// kept up to date by triggers.
+func (r *reactionRepository) Add(ctx context.Context, target string, targetID, userID uuid.UUID, reaction string) error {
+	table, err := lookupReactionTable(target)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s, user_id, type, created_at) VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING", table.reactions, table.column)
+	_, err = r.DB.ExecContext(ctx, query, targetID, userID, reaction, time.Now().UTC())
+	return err
+}
+
+func (r *reactionRepository) Remove(ctx context.Context, target string, targetID, userID uuid.UUID, reaction string) error {
+	table, err := lookupReactionTable(target)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = ? AND user_id = ? AND type = ?", table.reactions, table.column)
+	_, err = r.DB.ExecContext(ctx, query, targetID, userID, reaction)
+	return err
+}
+
+// GetCounts reads the counters of several posts or comments in one query.
+// Targets without reactions are absent from the result.
+func (r *reactionRepository) GetCounts(ctx context.Context, target string, targetIDs []uuid.UUID) (map[uuid.UUID]map[string]int, error) {
+	counts := make(map[uuid.UUID]map[string]int, len(targetIDs))
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+	table, err := lookupReactionTable(target)
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders, args := uuidList(targetIDs)
+	query := fmt.Sprintf("SELECT %s, type, count FROM %s WHERE %s IN (%s)", table.column, table.counts, table.column, placeholders)
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var reaction string
+		var count int
+		if err := rows.Scan(&id, &reaction, &count); err != nil {
+			return nil, err
+		}
+		if counts[id] == nil {
+			counts[id] = make(map[string]int)
+		}
+		counts[id][reaction] = count
+	}
+	return counts, rows.Err()
+}
+
+func (r *reactionRepository) GetUserReactions(ctx context.Context, target string, userID uuid.UUID, targetIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	reactions := make(map[uuid.UUID][]string)
+	if len(targetIDs) == 0 || userID == uuid.Nil {
+		return reactions, nil
+	}
+	table, err := lookupReactionTable(target)
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders, args := uuidList(targetIDs)
+	query := fmt.Sprintf("SELECT %s, type FROM %s WHERE user_id = ? AND %s IN (%s) ORDER BY type", table.column, table.reactions, table.column, placeholders)
+	rows, err := r.DB.QueryContext(ctx, query, append([]any{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var reaction string
+		if err := rows.Scan(&id, &reaction); err != nil {
+			return nil, err
+		}
+		reactions[id] = append(reactions[id], reaction)
+	}
+	return reactions, rows.Err()
+}
+
+// uuidList returns the placeholders and arguments of an IN clause.
+func uuidList(ids []uuid.UUID) (string, []any) {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "), args
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+const readingListSelect = `SELECT l.id, l.user_id, l.name, l.description, l.is_public, l.created_at, l.updated_at,
+	(SELECT COUNT(*) FROM reading_list_posts lp WHERE lp.list_id = l.id)
+	FROM reading_lists l`
+
+type readingListRepository struct {
+	DB *database.DB
+}
+
+func NewReadingListRepository(db *database.DB) interfaces.ReadingListRepository {
+	return &readingListRepository{DB: db}
+}
+
+func scanReadingList(row rowScanner) (*models.ReadingList, error) {
+	var list models.ReadingList
+	err := row.Scan(&list.ID, &list.UserID, &list.Name, &list.Description, &list.IsPublic, &list.CreatedAt, &list.UpdatedAt, &list.PostCount)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *readingListRepository) Create(ctx context.Context, list *models.ReadingList) (*models.ReadingList, error) {
+	listID := uuid.New()
+	now := time.Now().UTC()
+	query := "INSERT INTO reading_lists (id, user_id, name, description, is_public, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	if _, err := r.DB.ExecContext(ctx, query, listID, list.UserID, list.Name, list.Description, list.IsPublic, now, now); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, listID)
+}
+
+func (r *readingListRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ReadingList, error) {
+	list, err := scanReadingList(r.DB.QueryRowContext(ctx, readingListSelect+" WHERE l.id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("reading list not found")
+		}
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetByUserID returns the user's lists by name. publicOnly leaves out the
+// private ones.
+func (r *readingListRepository) GetByUserID(ctx context.Context, userID uuid.UUID, publicOnly bool) ([]*models.ReadingList, error) {
+	query := readingListSelect + " WHERE l.user_id = ?"
+	args := []any{userID}
+	if publicOnly {
+		query += " AND l.is_public = ?"
+		args = append(args, true)
+	}
+	rows, err := r.DB.QueryContext(ctx, query+" ORDER BY l.name", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lists []*models.ReadingList
+	for rows.Next() {
+		list, err := scanReadingList(rows)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return lists, rows.Err()
+}
+
+func (r *readingListRepository) Update(ctx context.Context, id uuid.UUID, list *models.ReadingList) (*models.ReadingList, error) {
+	query := "UPDATE reading_lists SET name = ?, description = ?, is_public = ?, updated_at = ? WHERE id = ?"
+	result, err := r.DB.ExecContext(ctx, query, list.Name, list.Description, list.IsPublic, time.Now().UTC(), id)
+	if err != nil {
+		return nil, err
+	}
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowAffected == 0 {
+		return nil, errors.New("reading list not found")
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *readingListRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM reading_lists WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowAffected == 0 {
+		return errors.New("reading list not found")
+	}
+	return nil
+}
+
+// AddPost is a no-op when the post is already on the list.
+func (r *readingListRepository) AddPost(ctx context.Context, listID, postID uuid.UUID) error {
+	query := "INSERT INTO reading_list_posts (list_id, post_id, added_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING"
+	_, err := r.DB.ExecContext(ctx, query, listID, postID, time.Now().UTC())
+	return err
+}
+
+func (r *readingListRepository) RemovePost(ctx context.Context, listID, postID uuid.UUID) error {
+	_, err := r.DB.ExecContext(ctx, "DELETE FROM reading_list_posts WHERE list_id = ? AND post_id = ?", listID, postID)
+	return err
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+// Match markers are private use characters so the stored text can be HTML
+// escaped before they are turned into <mark> tags.
+const (
+	markStart = "\ue000"
+	markEnd   = "\ue001"
+)
+
+type searchRepository struct {
+	DB *database.DB
+}
+
+func NewSearchRepository(db *database.DB) interfaces.SearchRepository {
+	return &searchRepository{DB: db}
+}
+
+func (r *searchRepository) SearchPosts(ctx context.Context, query string, opts models.ListOptions) ([]*models.SearchResult, string, error) {
+	offset, err := decodeOffsetCursor(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	normalizeLimit(&opts)
+
+	var sqlQuery string
+	var args []any
+	switch r.DB.Dialect {
+	case database.Postgres:
+		match, err := tsQuery(query)
+		if err != nil {
+			return nil, "", err
+		}
+		sqlQuery = `
+			SELECT p.id, p.id, ts_headline('simple', p.title, q, ?), ts_headline('simple', coalesce(p.content, ''), q, ?),
+				ts_rank_cd(p.search, q) AS rank, p.created_at
+			FROM posts p CROSS JOIN to_tsquery('simple', ?) q
+			WHERE p.search @@ q AND (p.status = ? OR p.user_id = ?)
+			ORDER BY rank DESC, p.id
+			LIMIT ? OFFSET ?`
+		args = []any{headlineOptions(true), headlineOptions(false), match}
+	default:
+		match, err := ftsQuery(query)
+		if err != nil {
+			return nil, "", err
+		}
+		sqlQuery = `
+			SELECT p.id, p.id, highlight(posts_fts, 1, ?, ?), snippet(posts_fts, 2, ?, ?, '…', 24),
+				-bm25(posts_fts, 0, 10.0, 1.0) AS rank, p.created_at
+			FROM posts_fts
+			JOIN posts p ON p.id = posts_fts.id
+			WHERE posts_fts MATCH ? AND (p.status = ? OR p.user_id = ?)
+			ORDER BY rank DESC, p.id
+			LIMIT ? OFFSET ?`
+		args = []any{markStart, markEnd, markStart, markEnd, match}
+	}
+	args = append(args, models.PostStatusPublished, opts.ViewerID, opts.Limit+1, offset)
+
+	rows, err := r.DB.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var results []*models.SearchResult
+	for rows.Next() {
+		result := models.SearchResult{Type: models.SearchTypePost}
+		if err := rows.Scan(&result.ID, &result.PostID, &result.Title, &result.Snippet, &result.Rank, &result.CreatedAt); err != nil {
+			return nil, "", err
+		}
+		result.Title = highlightHTML(result.Title)
+		result.Snippet = highlightHTML(result.Snippet)
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	return pageSearchResults(results, opts.Limit, offset)
+}
+
+func (r *searchRepository) SearchComments(ctx context.Context, query string, opts models.ListOptions) ([]*models.SearchResult, string, error) {
+	offset, err := decodeOffsetCursor(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	normalizeLimit(&opts)
+
+	var sqlQuery string
+	var args []any
+	switch r.DB.Dialect {
+	case database.Postgres:
+		match, err := tsQuery(query)
+		if err != nil {
+			return nil, "", err
+		}
+		sqlQuery = `
+			SELECT c.id, c.post_id, p.title, ts_headline('simple', coalesce(c.content, ''), q, ?),
+				ts_rank_cd(c.search, q) AS rank, c.created_at
+			FROM comments c
+			JOIN posts p ON p.id = c.post_id
+			CROSS JOIN to_tsquery('simple', ?) q
+			WHERE c.search @@ q AND (p.status = ? OR p.user_id = ?) AND c.status = ?
+			ORDER BY rank DESC, c.id
+			LIMIT ? OFFSET ?`
+		args = []any{headlineOptions(false), match}
+	default:
+		match, err := ftsQuery(query)
+		if err != nil {
+			return nil, "", err
+		}
+		sqlQuery = `
+			SELECT c.id, c.post_id, p.title, snippet(comments_fts, 1, ?, ?, '…', 24),
+				-bm25(comments_fts) AS rank, c.created_at
+			FROM comments_fts
+			JOIN comments c ON c.id = comments_fts.id
+			JOIN posts p ON p.id = c.post_id
+			WHERE comments_fts MATCH ? AND (p.status = ? OR p.user_id = ?) AND c.status = ?
+			ORDER BY rank DESC, c.id
+			LIMIT ? OFFSET ?`
+		args = []any{markStart, markEnd, match}
+	}
+	args = append(args, models.PostStatusPublished, opts.ViewerID, models.CommentStatusApproved, opts.Limit+1, offset)
+
+	rows, err := r.DB.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var results []*models.SearchResult
+	for rows.Next() {
+		result := models.SearchResult{Type: models.SearchTypeComment}
+		if err := rows.Scan(&result.ID, &result.PostID, &result.Title, &result.Snippet, &result.Rank, &result.CreatedAt); err != nil {
+			return nil, "", err
+		}
+		result.Title = html.EscapeString(result.Title)
+		result.Snippet = highlightHTML(result.Snippet)
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	return pageSearchResults(results, opts.Limit, offset)
+}
+
+func pageSearchResults(results []*models.SearchResult, limit, offset int) ([]*models.SearchResult, string, error) {
+	var next string
+	if len(results) > limit {
+		results = results[:limit]
+		next = encodeOffsetCursor(offset + limit)
+	}
+	return results, next, nil
+}
+
+type searchTerm struct {
+	word   string
+	prefix bool
+}
+
+// searchTerms splits free text into words. A trailing * asks for prefix
+// matching; any other query syntax is treated as plain text.
+func searchTerms(query string) ([]searchTerm, error) {
+	var terms []searchTerm
+	for _, word := range strings.Fields(query) {
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.Trim(word, "*")
+		if word == "" {
+			continue
+		}
+		terms = append(terms, searchTerm{word: word, prefix: prefix})
+	}
+	if len(terms) == 0 {
+		return nil, models.ErrEmptySearch
+	}
+	return terms, nil
+}
+
+// ftsQuery builds an SQLite FTS5 query with every word quoted.
+func ftsQuery(query string) (string, error) {
+	terms, err := searchTerms(query)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = `"` + strings.ReplaceAll(t.word, `"`, `""`) + `"`
+		if t.prefix {
+			parts[i] += "*"
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+var tsQuoter = strings.NewReplacer(`\`, `\\`, `'`, `''`)
+
+// tsQuery builds a Postgres tsquery with every word quoted as a lexeme.
+func tsQuery(query string) (string, error) {
+	terms, err := searchTerms(query)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = "'" + tsQuoter.Replace(t.word) + "'"
+		if t.prefix {
+			parts[i] += ":*"
+		}
+	}
+	return strings.Join(parts, " & "), nil
+}
+
+func headlineOptions(highlightAll bool) string {
+	if highlightAll {
+		return "StartSel=" + markStart + ", StopSel=" + markEnd + ", HighlightAll=true"
+	}
+	return "StartSel=" + markStart + ", StopSel=" + markEnd + ", MinWords=12, MaxWords=24"
+}
+
+func highlightHTML(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, markStart, "<mark>")
+	return strings.ReplaceAll(s, markEnd, "</mark>")
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+func TestSearchRepository(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		repo := NewSearchRepository(db)
+		alice := createUser(t, db, "alice")
+		bob := createUser(t, db, "bob")
+
+		gophers := createPost(t, db, alice.ID, "Gophers everywhere", models.PostStatusPublished)
+		createPost(t, db, alice.ID, "Unrelated", models.PostStatusPublished)
+		createPost(t, db, alice.ID, "Secret gophers", models.PostStatusDraft)
+		createComment(t, db, gophers.ID, bob.ID, nil, "I like gophers a lot")
+
+		results, _, err := repo.SearchPosts(ctx, "gophers", models.ListOptions{ViewerID: bob.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].ID != gophers.ID {
+			t.Fatalf("bob's search found %d posts, want only the published one", len(results))
+		}
+		if !strings.Contains(results[0].Title, "<mark>Gophers</mark>") {
+			t.Errorf("title = %q, want the match highlighted", results[0].Title)
+		}
+
+		results, _, err = repo.SearchPosts(ctx, "goph*", models.ListOptions{ViewerID: alice.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 2 {
+			t.Errorf("alice's prefix search found %d posts, want 2 with her draft", len(results))
+		}
+
+		results, _, err = repo.SearchComments(ctx, "gophers", models.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].PostID != gophers.ID {
+			t.Errorf("comment search found %d comments", len(results))
+		}
+
+		if _, _, err := repo.SearchPosts(ctx, " * ", models.ListOptions{}); !errors.Is(err, models.ErrEmptySearch) {
+			t.Errorf("empty query: err = %v, want ErrEmptySearch", err)
+		}
+		if _, _, err := repo.SearchPosts(ctx, "gophers", models.ListOptions{Cursor: "not a cursor"}); !errors.Is(err, models.ErrInvalidCursor) {
+			t.Errorf("bad cursor: err = %v, want ErrInvalidCursor", err)
+		}
+	})
+}
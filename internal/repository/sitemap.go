@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type sitemapRepository struct {
+	DB *database.DB
+}
+
+func NewSitemapRepository(db *database.DB) interfaces.SitemapRepository {
+	return &sitemapRepository{DB: db}
+}
+
+// GetEntries lists every published post, oldest first, followed by their
+// authors and tags. An author or tag was last modified when its latest post
+// was; that is worked out here rather than with MAX(), which SQLite returns
+// as text.
+func (r *sitemapRepository) GetEntries(ctx context.Context) ([]*models.SitemapEntry, error) {
+	query := "SELECT id, user_id, updated_at FROM posts WHERE status = ? ORDER BY created_at, id"
+	rows, err := r.DB.QueryContext(ctx, query, models.PostStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries, authors []*models.SitemapEntry
+	authorEntries := make(map[uuid.UUID]*models.SitemapEntry)
+	for rows.Next() {
+		var post models.SitemapEntry
+		var userID uuid.NullUUID
+		if err := rows.Scan(&post.ID, &userID, &post.LastMod); err != nil {
+			return nil, err
+		}
+		post.Kind = models.SitemapPost
+		entries = append(entries, &post)
+
+		// Posts of deleted users have no author page.
+		if !userID.Valid {
+			continue
+		}
+		author, ok := authorEntries[userID.UUID]
+		if !ok {
+			author = &models.SitemapEntry{Kind: models.SitemapAuthor, ID: userID.UUID}
+			authorEntries[userID.UUID] = author
+			authors = append(authors, author)
+		}
+		if post.LastMod.After(author.LastMod) {
+			author.LastMod = post.LastMod
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	entries = append(entries, authors...)
+
+	query = `SELECT t.tag, p.updated_at FROM post_tags t JOIN posts p ON p.id = t.post_id
+		WHERE p.status = ? ORDER BY t.tag`
+	tagRows, err := r.DB.QueryContext(ctx, query, models.PostStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	defer tagRows.Close()
+
+	var tag *models.SitemapEntry
+	for tagRows.Next() {
+		var name string
+		var lastMod time.Time
+		if err := tagRows.Scan(&name, &lastMod); err != nil {
+			return nil, err
+		}
+		if tag == nil || tag.Tag != name {
+			tag = &models.SitemapEntry{Kind: models.SitemapTag, Tag: name}
+			entries = append(entries, tag)
+		}
+		if lastMod.After(tag.LastMod) {
+			tag.LastMod = lastMod
+		}
+	}
+	return entries, tagRows.Err()
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestSitemapRepository(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		alice := createUser(t, db, "alice")
+		bob := createUser(t, db, "bob")
+		createPost(t, db, alice.ID, "First", models.PostStatusPublished, "go")
+		createPost(t, db, alice.ID, "Second", models.PostStatusPublished, "go", "sql")
+		createPost(t, db, alice.ID, "Draft", models.PostStatusDraft, "secret")
+		createPost(t, db, bob.ID, "Orphaned", models.PostStatusPublished)
+		if err := NewUserRepository(db).Delete(ctx, bob.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		entries, err := NewSitemapRepository(db).GetEntries(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		kinds := make(map[string]int)
+		var authors []uuid.UUID
+		var tags []string
+		for _, entry := range entries {
+			kinds[entry.Kind]++
+			switch entry.Kind {
+			case models.SitemapAuthor:
+				authors = append(authors, entry.ID)
+			case models.SitemapTag:
+				tags = append(tags, entry.Tag)
+			}
+		}
+		if kinds[models.SitemapPost] != 3 {
+			t.Errorf("post entries = %d, want 3", kinds[models.SitemapPost])
+		}
+		if len(authors) != 1 || authors[0] != alice.ID {
+			t.Errorf("author entries = %v, want only alice's", authors)
+		}
+		if len(tags) != 2 || tags[0] != "go" || tags[1] != "sql" {
+			t.Errorf("tag entries = %v, want [go sql]", tags)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type spamRepository struct {
+	DB *database.DB
+}
+
+func NewSpamRepository(db *database.DB) interfaces.SpamRepository {
+	return &spamRepository{DB: db}
+}
+
+func (r *spamRepository) GetTokens(ctx context.Context, tokens []string) (map[string]models.SpamToken, error) {
+	counts := make(map[string]models.SpamToken, len(tokens))
+	if len(tokens) == 0 {
+		return counts, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(tokens)), ", ")
+	args := make([]any, len(tokens))
+	for i, token := range tokens {
+		args[i] = token
+	}
+	rows, err := r.DB.QueryContext(ctx, "SELECT token, spam, ham FROM spam_tokens WHERE token IN ("+placeholders+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var token models.SpamToken
+		if err := rows.Scan(&token.Token, &token.Spam, &token.Ham); err != nil {
+			return nil, err
+		}
+		counts[token.Token] = token
+	}
+	return counts, rows.Err()
+}
+
+// AdjustTokens adds delta to the spam or ham count of every token.
+func (r *spamRepository) AdjustTokens(ctx context.Context, tokens []string, spam bool, delta int) error {
+	spamDelta, hamDelta := 0, delta
+	if spam {
+		spamDelta, hamDelta = delta, 0
+	}
+	query := `INSERT INTO spam_tokens (token, spam, ham) VALUES (?, ?, ?)
+		ON CONFLICT (token) DO UPDATE SET spam = spam_tokens.spam + excluded.spam, ham = spam_tokens.ham + excluded.ham`
+	for _, token := range tokens {
+		if _, err := r.DB.ExecContext(ctx, query, token, spamDelta, hamDelta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTraining returns the label a comment was learned as, or an empty label
+// if the model hasn't seen it.
+func (r *spamRepository) GetTraining(ctx context.Context, commentID uuid.UUID) (string, []string, error) {
+	var label, tokens string
+	err := r.DB.QueryRowContext(ctx, "SELECT label, tokens FROM spam_training WHERE comment_id = ?", commentID).Scan(&label, &tokens)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+	return label, strings.Fields(tokens), nil
+}
+
+func (r *spamRepository) SaveTraining(ctx context.Context, commentID uuid.UUID, label string, tokens []string) error {
+	query := `INSERT INTO spam_training (comment_id, label, tokens, trained_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (comment_id) DO UPDATE SET label = excluded.label, tokens = excluded.tokens, trained_at = excluded.trained_at`
+	_, err := r.DB.ExecContext(ctx, query, commentID, label, strings.Join(tokens, " "), time.Now().UTC())
+	return err
+}
+
+// GetTrainingSet returns every comment a moderator has marked as spam or
+// approved.
+func (r *spamRepository) GetTrainingSet(ctx context.Context) ([]*models.SpamSample, error) {
+	query := "SELECT id, content, status FROM comments WHERE status IN (?, ?) AND deleted_at IS NULL"
+	rows, err := r.DB.QueryContext(ctx, query, models.CommentStatusSpam, models.CommentStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []*models.SpamSample
+	for rows.Next() {
+		var sample models.SpamSample
+		var status string
+		if err := rows.Scan(&sample.CommentID, &sample.Content, &status); err != nil {
+			return nil, err
+		}
+		sample.Spam = status == models.CommentStatusSpam
+		samples = append(samples, &sample)
+	}
+	return samples, rows.Err()
+}
+
+func (r *spamRepository) GetStats(ctx context.Context, topTokens int) (*models.SpamStats, error) {
+	var stats models.SpamStats
+	rows, err := r.DB.QueryContext(ctx, "SELECT label, COUNT(*) FROM spam_training GROUP BY label")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var label string
+		var count int
+		if err := rows.Scan(&label, &count); err != nil {
+			return nil, err
+		}
+		if label == models.SpamVerdictSpam {
+			stats.SpamDocuments = count
+		} else {
+			stats.HamDocuments = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM spam_tokens").Scan(&stats.Tokens); err != nil {
+		return nil, err
+	}
+
+	tokenRows, err := r.DB.QueryContext(ctx, "SELECT token, spam, ham FROM spam_tokens WHERE spam > ham ORDER BY spam - ham DESC, token LIMIT ?", topTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer tokenRows.Close()
+	for tokenRows.Next() {
+		var token models.SpamToken
+		if err := tokenRows.Scan(&token.Token, &token.Spam, &token.Ham); err != nil {
+			return nil, err
+		}
+		stats.TopTokens = append(stats.TopTokens, token)
+	}
+	return &stats, tokenRows.Err()
+}
+
+func (r *spamRepository) Reset(ctx context.Context) error {
+	if _, err := r.DB.ExecContext(ctx, "DELETE FROM spam_training"); err != nil {
+		return err
+	}
+	_, err := r.DB.ExecContext(ctx, "DELETE FROM spam_tokens")
+	return err
+}
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+)
+
+func NewTxManager(db *database.DB) interfaces.TxManager {
+	return db
+}
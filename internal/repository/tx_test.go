@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+func TestTxManager(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		txManager := NewTxManager(db)
+		posts := NewPostRepository(db)
+		alice := createUser(t, db, "alice")
+
+		errRollback := errors.New("roll back")
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if _, err := posts.Create(ctx, &models.Post{Title: "Rolled back", Status: models.PostStatusPublished, UserID: alice.ID, Tags: []string{"go"}}); err != nil {
+				return err
+			}
+			// Nested calls join the outer transaction.
+			return txManager.WithinTx(ctx, func(ctx context.Context) error {
+				if n := count(t, db, "posts", "1 = 1"); n != 0 {
+					t.Errorf("a query outside the transaction sees %d posts", n)
+				}
+				return errRollback
+			})
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("WithinTx = %v", err)
+		}
+		if n := count(t, db, "posts", "1 = 1"); n != 0 {
+			t.Errorf("%d posts left after rolling back", n)
+		}
+		if n := count(t, db, "post_tags", "1 = 1"); n != 0 {
+			t.Errorf("%d tags left after rolling back", n)
+		}
+
+		err = txManager.WithinTx(ctx, func(ctx context.Context) error {
+			_, err := posts.Create(ctx, &models.Post{Title: "Committed", Status: models.PostStatusPublished, UserID: alice.ID})
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := count(t, db, "posts", "title = ?", "Committed"); n != 1 {
+			t.Errorf("%d committed posts, want 1", n)
+		}
+	})
+}
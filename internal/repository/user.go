@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"time"
 
+	"github.com/ahmetilboga2004/go-blog/config/database"
 	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
 	"github.com/ahmetilboga2004/go-blog/internal/models"
 	"github.com/ahmetilboga2004/go-blog/pkg/utils"
@@ -11,14 +14,14 @@ import (
 )
 
 type userRepository struct {
-	DB *sql.DB
+	DB *database.DB
 }
 
-func NewUserRepository(db *sql.DB) interfaces.UserRepository {
+func NewUserRepository(db *database.DB) interfaces.UserRepository {
 	return &userRepository{DB: db}
 }
 
-func (r *userRepository) Create(user *models.User) (*models.User, error) {
+func (r *userRepository) Create(ctx context.Context, user *models.User) (*models.User, error) {
 	userID := uuid.New()
 
 	salt, err := utils.GenerateSalt()
@@ -28,42 +31,68 @@ func (r *userRepository) Create(user *models.User) (*models.User, error) {
 
 	hashedPassword := utils.HashPassword(user.Password, salt)
 
-	query := `INSERT INTO users (id, firstName, lastName, username, email, password, salt) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id, firstName, lastName, username, email, password, salt`
-	err = r.DB.QueryRow(query, userID, user.FirstName, user.LastName, user.Username, user.Email, hashedPassword, salt).Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username, &user.Email, &user.Password, &user.Salt)
+	now := time.Now().UTC()
+	query := `INSERT INTO users (id, firstName, lastName, username, email, password, salt, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, firstName, lastName, username, email, password, salt, role, created_at, updated_at`
+	err = r.DB.QueryRowContext(ctx, query, userID, user.FirstName, user.LastName, user.Username, user.Email, hashedPassword, salt, now, now).Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username, &user.Email, &user.Password, &user.Salt, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (r *userRepository) GetAll() ([]*models.User, error) {
-	rows, err := r.DB.Query("SELECT id, firstName, lastName, username, email FROM users")
+var userSorts = map[string]sortColumn{
+	"createdAt": {column: "created_at", isTime: true},
+	"username":  {column: "username"},
+}
+
+func (r *userRepository) GetAll(ctx context.Context, opts models.ListOptions) ([]*models.User, string, error) {
+	var q listQuery
+	q.addDateRange("created_at", &opts)
+	query, sort, err := q.build("SELECT id, firstName, lastName, username, email, role, created_at, updated_at FROM users", "id", &opts, userSorts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, "", err
 	}
 	defer rows.Close()
 
 	var users []*models.User
 	for rows.Next() {
 		var user models.User
-		if err := rows.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username, &user.Email); err != nil {
-			return nil, err
+		if err := rows.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, "", err
 		}
 		users = append(users, &user)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return users, nil
+	var next string
+	if len(users) > opts.Limit {
+		users = users[:opts.Limit]
+		last := users[len(users)-1]
+		next = nextCursor(&opts, sort, userSortValue(last, opts.Sort), last.ID)
+	}
+	return users, next, nil
+}
+
+func userSortValue(user *models.User, sort string) any {
+	if sort == "username" {
+		return user.Username
+	}
+	return user.CreatedAt
 }
 
-func (r *userRepository) GetByID(id uuid.UUID) (*models.User, error) {
-	query := `SELECT id, firstName, lastName, username, email FROM users WHERE id = ?`
-	rows := r.DB.QueryRow(query, id)
+func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	query := `SELECT id, firstName, lastName, username, email, role, created_at, updated_at FROM users WHERE id = ?`
+	rows := r.DB.QueryRowContext(ctx, query, id)
 	var user models.User
-	if err := rows.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username, &user.Email); err != nil {
+	if err := rows.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("user not found")
 		}
@@ -72,10 +101,10 @@ func (r *userRepository) GetByID(id uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) FindByUsernameOrEmail(username, email string) (*models.User, error) {
-	query := `SELECT id, firstName, lastName, username, email, password, salt FROM users WHERE username = ? OR email = ?`
+func (r *userRepository) FindByUsernameOrEmail(ctx context.Context, username, email string) (*models.User, error) {
+	query := `SELECT id, firstName, lastName, username, email, password, salt, role FROM users WHERE username = ? OR email = ?`
 	user := &models.User{}
-	err := r.DB.QueryRow(query, username, email).Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username, &user.Email, &user.Password, &user.Salt)
+	err := r.DB.QueryRowContext(ctx, query, username, email).Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username, &user.Email, &user.Password, &user.Salt, &user.Role)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -86,10 +115,10 @@ func (r *userRepository) FindByUsernameOrEmail(username, email string) (*models.
 	return user, nil
 }
 
-func (r *userRepository) Update(id uuid.UUID, user *models.User) (*models.User, error) {
-	query := `UPDATE users SET firstName = ?, lastName = ?, username = ?, email = ?, password = ? WHERE id = ? RETURNING id, firstName, lastName, username, email`
-	row := r.DB.QueryRow(query, user.FirstName, user.LastName, user.Username, user.Email, user.Password, id)
-	if err := row.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username, &user.Email); err != nil {
+func (r *userRepository) Update(ctx context.Context, id uuid.UUID, user *models.User) (*models.User, error) {
+	query := `UPDATE users SET firstName = ?, lastName = ?, username = ?, email = ?, password = ?, updated_at = ? WHERE id = ? RETURNING id, firstName, lastName, username, email, role, created_at, updated_at`
+	row := r.DB.QueryRowContext(ctx, query, user.FirstName, user.LastName, user.Username, user.Email, user.Password, time.Now().UTC(), id)
+	if err := row.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("user not found")
 		}
@@ -98,9 +127,9 @@ func (r *userRepository) Update(id uuid.UUID, user *models.User) (*models.User,
 	return user, nil
 }
 
-func (r *userRepository) Delete(id uuid.UUID) error {
+func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := "DELETE FROM users WHERE id = ?"
-	result, err := r.DB.Exec(query, id)
+	result, err := r.DB.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -113,3 +142,19 @@ func (r *userRepository) Delete(id uuid.UUID) error {
 	}
 	return nil
 }
+
+func (r *userRepository) UpdateRole(ctx context.Context, id uuid.UUID, role string) error {
+	query := "UPDATE users SET role = ?, updated_at = ? WHERE id = ?"
+	result, err := r.DB.ExecContext(ctx, query, role, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
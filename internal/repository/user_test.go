@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+func TestUserRepository(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		repo := NewUserRepository(db)
+
+		alice := createUser(t, db, "alice")
+		if alice.Role != models.RoleUser {
+			t.Errorf("role = %q, want %q", alice.Role, models.RoleUser)
+		}
+		if alice.CreatedAt.IsZero() || alice.Password == "password1" {
+			t.Errorf("created user = %+v, want a timestamp and a hashed password", alice)
+		}
+
+		got, err := repo.GetByID(ctx, alice.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Username != "alice" || got.Email != "alice@example.com" {
+			t.Errorf("GetByID = %+v", got)
+		}
+
+		found, err := repo.FindByUsernameOrEmail(ctx, "alice@example.com", "alice@example.com")
+		if err != nil || found == nil || found.ID != alice.ID {
+			t.Fatalf("FindByUsernameOrEmail = %v, %v", found, err)
+		}
+		missing, err := repo.FindByUsernameOrEmail(ctx, "nobody", "nobody")
+		if err != nil || missing != nil {
+			t.Errorf("FindByUsernameOrEmail of a missing user = %v, %v", missing, err)
+		}
+
+		if _, err := repo.Create(ctx, &models.User{FirstName: "A", LastName: "B", Username: "alice", Email: "other@example.com", Password: "password1"}); err == nil {
+			t.Error("creating a second alice succeeded")
+		}
+
+		if err := repo.UpdateRole(ctx, alice.ID, models.RoleAdmin); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := repo.GetByID(ctx, alice.ID); got.Role != models.RoleAdmin {
+			t.Errorf("role after UpdateRole = %q", got.Role)
+		}
+
+		if err := repo.Delete(ctx, alice.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.GetByID(ctx, alice.ID); err == nil {
+			t.Error("deleted user is still found")
+		}
+		if err := repo.Delete(ctx, alice.ID); err == nil {
+			t.Error("deleting a missing user succeeded")
+		}
+	})
+}
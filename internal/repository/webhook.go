@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+const webhookSelect = "SELECT id, user_id, url, secret, events, is_global, active, created_at, updated_at FROM webhooks"
+
+type webhookRepository struct {
+	DB *database.DB
+}
+
+func NewWebhookRepository(db *database.DB) interfaces.WebhookRepository {
+	return &webhookRepository{DB: db}
+}
+
+// Events are stored as a comma separated list.
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	var webhook models.Webhook
+	var events string
+	err := row.Scan(&webhook.ID, &webhook.UserID, &webhook.URL, &webhook.Secret, &events, &webhook.Global, &webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Events = strings.Split(events, ",")
+	return &webhook, nil
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	webhookID := uuid.New()
+	now := time.Now().UTC()
+	query := "INSERT INTO webhooks (id, user_id, url, secret, events, is_global, active, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := r.DB.ExecContext(ctx, query, webhookID, webhook.UserID, webhook.URL, webhook.Secret, strings.Join(webhook.Events, ","), webhook.Global, webhook.Active, now, now)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, webhookID)
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	webhook, err := scanWebhook(r.DB.QueryRowContext(ctx, webhookSelect+" WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("webhook not found")
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (r *webhookRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error) {
+	return r.query(ctx, webhookSelect+" WHERE user_id = ? ORDER BY created_at, id", userID)
+}
+
+// GetSubscribed returns the active webhooks that should hear about event on
+// a post of ownerID: the owner's own and the global ones.
+func (r *webhookRepository) GetSubscribed(ctx context.Context, event string, ownerID uuid.UUID) ([]*models.Webhook, error) {
+	webhooks, err := r.query(ctx, webhookSelect+" WHERE active = ? AND (is_global = ? OR user_id = ?)", true, true, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(webhooks, func(webhook *models.Webhook) bool {
+		return !slices.Contains(webhook.Events, event)
+	}), nil
+}
+
+func (r *webhookRepository) query(ctx context.Context, query string, args ...any) ([]*models.Webhook, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*models.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r *webhookRepository) Update(ctx context.Context, id uuid.UUID, webhook *models.Webhook) (*models.Webhook, error) {
+	query := "UPDATE webhooks SET url = ?, secret = ?, events = ?, is_global = ?, active = ?, updated_at = ? WHERE id = ?"
+	result, err := r.DB.ExecContext(ctx, query, webhook.URL, webhook.Secret, strings.Join(webhook.Events, ","), webhook.Global, webhook.Active, time.Now().UTC(), id)
+	if err != nil {
+		return nil, err
+	}
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowAffected == 0 {
+		return nil, errors.New("webhook not found")
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM webhooks WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowAffected == 0 {
+		return errors.New("webhook not found")
+	}
+	return nil
+}
+
+const deliverySelect = `SELECT id, webhook_id, event, payload, status, attempts, next_attempt_at, last_attempt_at,
+	response_status, response_body, error, created_at
+	FROM webhook_deliveries`
+
+type webhookDeliveryRepository struct {
+	DB *database.DB
+}
+
+func NewWebhookDeliveryRepository(db *database.DB) interfaces.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{DB: db}
+}
+
+func scanDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var lastAttemptAt sql.NullTime
+	err := row.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt, &lastAttemptAt,
+		&d.ResponseStatus, &d.ResponseBody, &d.Error, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if lastAttemptAt.Valid {
+		d.LastAttemptAt = &lastAttemptAt.Time
+	}
+	return &d, nil
+}
+
+// Create queues a delivery to be sent right away.
+func (r *webhookDeliveryRepository) Create(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	deliveryID := uuid.New()
+	now := time.Now().UTC()
+	query := "INSERT INTO webhook_deliveries (id, webhook_id, event, payload, status, next_attempt_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	_, err := r.DB.ExecContext(ctx, query, deliveryID, delivery.WebhookID, delivery.Event, delivery.Payload, models.DeliveryStatusPending, now, now)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, deliveryID)
+}
+
+func (r *webhookDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	delivery, err := scanDelivery(r.DB.QueryRowContext(ctx, deliverySelect+" WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("delivery not found")
+		}
+		return nil, err
+	}
+	return delivery, nil
+}
+
+var deliverySorts = map[string]sortColumn{
+	"createdAt": {column: "created_at", isTime: true},
+}
+
+// GetByWebhookID lists the deliveries of a webhook, newest first.
+func (r *webhookDeliveryRepository) GetByWebhookID(ctx context.Context, webhookID uuid.UUID, opts models.ListOptions) ([]*models.WebhookDelivery, string, error) {
+	var q listQuery
+	q.add("webhook_id = ?", webhookID)
+	opts.Sort = "createdAt"
+	opts.Desc = true
+	query, sort, err := q.build(deliverySelect, "id", &opts, deliverySorts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(deliveries) > opts.Limit {
+		deliveries = deliveries[:opts.Limit]
+		last := deliveries[len(deliveries)-1]
+		next = nextCursor(&opts, sort, last.CreatedAt, last.ID)
+	}
+	return deliveries, next, nil
+}
+
+// GetDue returns pending deliveries whose next attempt is due, oldest first.
+func (r *webhookDeliveryRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	query := deliverySelect + " WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at LIMIT ?"
+	rows, err := r.DB.QueryContext(ctx, query, models.DeliveryStatusPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// Claim pushes the next attempt of a delivery that is due at now back to
+// until, so no other worker picks it up meanwhile. It reports false if
+// another worker got there first.
+func (r *webhookDeliveryRepository) Claim(ctx context.Context, delivery *models.WebhookDelivery, now, until time.Time) (bool, error) {
+	query := "UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?"
+	result, err := r.DB.ExecContext(ctx, query, until, delivery.ID, models.DeliveryStatusPending, now)
+	if err != nil {
+		return false, err
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if claimed > 0 {
+		delivery.NextAttemptAt = until
+	}
+	return claimed > 0, nil
+}
+
+// SaveAttempt records the outcome of an attempt and when to try next.
+func (r *webhookDeliveryRepository) SaveAttempt(ctx context.Context, d *models.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = ?,
+		response_status = ?, response_body = ?, error = ? WHERE id = ?`
+	_, err := r.DB.ExecContext(ctx, query, d.Status, d.Attempts, d.NextAttemptAt, d.LastAttemptAt,
+		d.ResponseStatus, d.ResponseBody, d.Error, d.ID)
+	return err
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+func TestWebhookDeliveryClaim(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		alice := createUser(t, db, "alice")
+		webhook, err := NewWebhookRepository(db).Create(ctx, &models.Webhook{
+			UserID: alice.ID,
+			URL:    "https://example.com/hook",
+			Secret: "secret",
+			Events: []string{models.EventPostPublished},
+			Active: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo := NewWebhookDeliveryRepository(db)
+		created, err := repo.Create(ctx, &models.WebhookDelivery{WebhookID: webhook.ID, Event: models.EventPostPublished, Payload: "{}"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		due := func(at time.Time) []*models.WebhookDelivery {
+			deliveries, err := repo.GetDue(ctx, at, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return deliveries
+		}
+		now := time.Now().UTC()
+		if got := due(now); len(got) != 1 || got[0].ID != created.ID {
+			t.Fatalf("due deliveries = %d, want the new one", len(got))
+		}
+
+		// Every caller saw the delivery as due, but only one may send it.
+		var claims atomic.Int32
+		var wg sync.WaitGroup
+		for range 4 {
+			delivery := *created
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				claimed, err := repo.Claim(ctx, &delivery, now, now.Add(time.Minute))
+				if err != nil {
+					t.Error(err)
+				}
+				if claimed {
+					claims.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		if n := claims.Load(); n != 1 {
+			t.Fatalf("delivery claimed %d times, want once", n)
+		}
+
+		if got := due(now); len(got) != 0 {
+			t.Errorf("claimed delivery is still due")
+		}
+		if got := due(now.Add(2 * time.Minute)); len(got) != 1 {
+			t.Errorf("delivery isn't due again after its lease ran out")
+		}
+	})
+}
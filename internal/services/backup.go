@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+type backupService struct {
+	backupRepo interfaces.BackupRepository
+}
+
+func NewBackupService(backupRepo interfaces.BackupRepository) interfaces.BackupService {
+	return &backupService{
+		backupRepo: backupRepo,
+	}
+}
+
+func (s *backupService) CreateBackup(ctx context.Context) (*models.Backup, error) {
+	return s.backupRepo.Create(ctx)
+}
+
+func (s *backupService) GetAllBackups(ctx context.Context) ([]*models.Backup, error) {
+	return s.backupRepo.GetAll(ctx)
+}
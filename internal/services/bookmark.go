@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type bookmarkService struct {
+	bookmarkRepo interfaces.BookmarkRepository
+	postRepo     interfaces.PostRepository
+	postService  interfaces.PostService
+}
+
+// NewBookmarkService lists bookmarks through postService, so they come with
+// the same comment counts and reactions as any other post list.
+func NewBookmarkService(bookmarkRepo interfaces.BookmarkRepository, postRepo interfaces.PostRepository, postService interfaces.PostService) interfaces.BookmarkService {
+	return &bookmarkService{
+		bookmarkRepo: bookmarkRepo,
+		postRepo:     postRepo,
+		postService:  postService,
+	}
+}
+
+func (s *bookmarkService) AddBookmark(ctx context.Context, userId, postId uuid.UUID) error {
+	post, err := s.postRepo.GetByID(ctx, postId)
+	if err != nil {
+		return err
+	}
+	if !post.VisibleTo(userId) {
+		return errors.New("post not found")
+	}
+	return s.bookmarkRepo.Add(ctx, userId, postId)
+}
+
+func (s *bookmarkService) RemoveBookmark(ctx context.Context, userId, postId uuid.UUID) error {
+	return s.bookmarkRepo.Remove(ctx, userId, postId)
+}
+
+// GetBookmarks lists the bookmarked posts the user can still see; posts
+// turned back into drafts by their author drop out until published again.
+func (s *bookmarkService) GetBookmarks(ctx context.Context, userId uuid.UUID, opts models.ListOptions) ([]*models.Post, string, error) {
+	opts.BookmarkedBy = userId
+	opts.ViewerID = userId
+	return s.postService.GetAllPosts(ctx, opts)
+}
+
+// attachBookmarks marks the posts the viewer has bookmarked with one query.
+func attachBookmarks(ctx context.Context, bookmarkRepo interfaces.BookmarkRepository, viewerId uuid.UUID, posts []*models.Post) error {
+	ids := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	bookmarked, err := bookmarkRepo.GetBookmarked(ctx, viewerId, ids)
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		post.IsBookmarked = bookmarked[post.ID]
+	}
+	return nil
+}
@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// maxCollabContent matches the content limit of posts.
+const maxCollabContent = 100000
+
+var errNotDraft = errors.New("only drafts can be edited together")
+
+type collabService struct {
+	postRepo     interfaces.PostRepository
+	coAuthorRepo interfaces.CoAuthorRepository
+	userRepo     interfaces.UserRepository
+	txManager    interfaces.TxManager
+	saveInterval time.Duration
+	historySize  int
+	bufferSize   int
+
+	mu        sync.Mutex
+	documents map[uuid.UUID]*collabDocument
+}
+
+// NewCollabService lets the author of a draft and their co-authors edit it
+// together. Open drafts are kept in memory and saved to the post every
+// saveInterval and when the last collaborator leaves. Edits may be made on
+// any of the last historySize revisions; each connection buffers bufferSize
+// messages and is dropped if it falls further behind.
+func NewCollabService(postRepo interfaces.PostRepository, coAuthorRepo interfaces.CoAuthorRepository, userRepo interfaces.UserRepository, txManager interfaces.TxManager, saveInterval time.Duration, historySize, bufferSize int) interfaces.CollabService {
+	return &collabService{
+		postRepo:     postRepo,
+		coAuthorRepo: coAuthorRepo,
+		userRepo:     userRepo,
+		txManager:    txManager,
+		saveInterval: saveInterval,
+		historySize:  historySize,
+		bufferSize:   bufferSize,
+		documents:    make(map[uuid.UUID]*collabDocument),
+	}
+}
+
+// GetCoAuthors lists the co-authors of a post. Those of a draft are only
+// shown to the people who can edit it.
+func (s *collabService) GetCoAuthors(ctx context.Context, viewerId, postId uuid.UUID) ([]*models.CoAuthor, error) {
+	post, err := s.postRepo.GetByID(ctx, postId)
+	if err != nil {
+		return nil, errors.New("post not found")
+	}
+	if !post.VisibleTo(viewerId) {
+		isCoAuthor, err := s.coAuthorRepo.IsCoAuthor(ctx, postId, viewerId)
+		if err != nil {
+			return nil, err
+		}
+		if !isCoAuthor {
+			return nil, errors.New("post not found")
+		}
+	}
+	return s.coAuthorRepo.GetByPostID(ctx, postId)
+}
+
+// AddCoAuthor lets the author of a post invite another user to edit it.
+func (s *collabService) AddCoAuthor(ctx context.Context, userId, postId, coAuthorId uuid.UUID) error {
+	post, err := s.postRepo.GetByID(ctx, postId)
+	if err != nil {
+		return errors.New("post not found")
+	}
+	if post.UserID != userId {
+		return errors.New("unauthorized user")
+	}
+	if coAuthorId == userId {
+		return errors.New("you are already the author of this post")
+	}
+	if _, err := s.userRepo.GetByID(ctx, coAuthorId); err != nil {
+		return errors.New("user not found")
+	}
+	return s.coAuthorRepo.Add(ctx, postId, coAuthorId)
+}
+
+// RemoveCoAuthor lets the author remove a co-author, or a co-author step
+// down. Their open editor connections are closed.
+func (s *collabService) RemoveCoAuthor(ctx context.Context, userId, postId, coAuthorId uuid.UUID) error {
+	post, err := s.postRepo.GetByID(ctx, postId)
+	if err != nil {
+		return errors.New("post not found")
+	}
+	if post.UserID != userId && coAuthorId != userId {
+		return errors.New("unauthorized user")
+	}
+	if err := s.coAuthorRepo.Remove(ctx, postId, coAuthorId); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := s.documents[postId]; d != nil {
+		d.mu.Lock()
+		for c := range d.clients {
+			if c.user.ID == coAuthorId {
+				d.drop(c)
+			}
+		}
+		d.mu.Unlock()
+	}
+	return nil
+}
+
+// Join connects the user to the editor of a draft they can edit. The session
+// starts with a snapshot of the document.
+func (s *collabService) Join(ctx context.Context, userId, postId uuid.UUID) (interfaces.CollabSession, error) {
+	post, err := s.postRepo.GetByID(ctx, postId)
+	if err != nil {
+		return nil, errors.New("post not found")
+	}
+	if post.Status != models.PostStatusDraft {
+		return nil, errNotDraft
+	}
+	if post.UserID != userId {
+		isCoAuthor, err := s.coAuthorRepo.IsCoAuthor(ctx, postId, userId)
+		if err != nil {
+			return nil, err
+		}
+		if !isCoAuthor {
+			return nil, errors.New("unauthorized user")
+		}
+	}
+	user, err := s.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.documents[postId]
+	if d == nil {
+		// Read the post again now that no one else can open the document,
+		// in case the last session saved it in the meantime.
+		post, err = s.postRepo.GetByID(ctx, postId)
+		if err != nil {
+			return nil, errors.New("post not found")
+		}
+		d = &collabDocument{
+			postID:  postId,
+			title:   post.Title,
+			content: []rune(post.Content),
+			clients: make(map[*collabClient]struct{}),
+			done:    make(chan struct{}),
+		}
+		s.documents[postId] = d
+		go s.autosave(d)
+	}
+
+	c := &collabClient{
+		service:  s,
+		doc:      d,
+		clientID: uuid.New(),
+		user: models.Author{
+			ID:          user.ID,
+			Username:    user.Username,
+			DisplayName: strings.TrimSpace(user.FirstName + " " + user.LastName),
+		},
+		messages: make(chan *models.CollabMessage, s.bufferSize),
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.join(c)
+	return c, nil
+}
+
+// leave ends a session and closes the document once no one is editing it.
+// The last save happens under s.mu so a new session can't load the post
+// before it is written.
+func (s *collabService) leave(c *collabClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := c.doc
+	d.mu.Lock()
+	d.drop(c)
+	empty := len(d.clients) == 0
+	d.mu.Unlock()
+
+	if empty && s.documents[d.postID] == d {
+		delete(s.documents, d.postID)
+		close(d.done)
+		s.save(d)
+	}
+}
+
+func (s *collabService) autosave(d *collabDocument) {
+	ticker := time.NewTicker(s.saveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.save(d)
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// save writes the document to the post if it changed since the last save.
+// The title and status of the post are left as they are; once it is
+// published or deleted, the editor's changes are no longer saved.
+func (s *collabService) save(d *collabDocument) {
+	d.saveMu.Lock()
+	defer d.saveMu.Unlock()
+
+	d.mu.Lock()
+	if !d.dirty {
+		d.mu.Unlock()
+		return
+	}
+	content := string(d.content)
+	d.dirty = false
+	d.mu.Unlock()
+
+	html, err := utils.RenderMarkdown(content)
+	if err == nil {
+		err = s.txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+			post, err := s.postRepo.GetByID(ctx, d.postID)
+			if err != nil {
+				return err
+			}
+			if post.Status != models.PostStatusDraft {
+				return errNotDraft
+			}
+			post.Content, post.ContentHTML = content, html
+			_, err = s.postRepo.Update(ctx, d.postID, post)
+			return err
+		})
+	}
+	if err != nil {
+		utils.Log(utils.WARNING, "Failed to save collaborative draft %s: %v", d.postID, err)
+		if !errors.Is(err, errNotDraft) {
+			d.mu.Lock()
+			d.dirty = true
+			d.mu.Unlock()
+		}
+	}
+}
+
+// collabDocument is the in-memory state of a draft being edited.
+type collabDocument struct {
+	postID uuid.UUID
+	title  string
+	done   chan struct{}
+	saveMu sync.Mutex
+
+	mu       sync.Mutex
+	content  []rune
+	revision int
+	history  []models.TextOperation // the last operations, ending at revision
+	dirty    bool
+	clients  map[*collabClient]struct{}
+}
+
+// join must be called with d.mu held, as must the other methods.
+func (d *collabDocument) join(c *collabClient) {
+	snapshot := &models.CollabMessage{
+		Type:     models.CollabMessageSnapshot,
+		Revision: d.revision,
+		Title:    d.title,
+		Content:  string(d.content),
+	}
+	for other := range d.clients {
+		snapshot.Collaborators = append(snapshot.Collaborators, other.collaborator())
+	}
+	snapshot.Collaborators = append(snapshot.Collaborators, c.collaborator())
+
+	d.clients[c] = struct{}{}
+	d.send(c, snapshot)
+	d.broadcast(c, &models.CollabMessage{Type: models.CollabMessageJoin, Collaborator: c.collaborator()})
+}
+
+// send queues a message, dropping a client that has fallen too far behind.
+func (d *collabDocument) send(c *collabClient, message *models.CollabMessage) {
+	if _, ok := d.clients[c]; !ok {
+		return
+	}
+	select {
+	case c.messages <- message:
+	default:
+		d.drop(c)
+	}
+}
+
+// broadcast sends a message to everyone but from.
+func (d *collabDocument) broadcast(from *collabClient, message *models.CollabMessage) {
+	for c := range d.clients {
+		if c != from {
+			d.send(c, message)
+		}
+	}
+}
+
+func (d *collabDocument) drop(c *collabClient) {
+	if _, ok := d.clients[c]; !ok {
+		return
+	}
+	delete(d.clients, c)
+	close(c.messages)
+	d.broadcast(c, &models.CollabMessage{Type: models.CollabMessageLeave, Collaborator: c.collaborator()})
+}
+
+type collabClient struct {
+	service  *collabService
+	doc      *collabDocument
+	clientID uuid.UUID
+	user     models.Author
+	messages chan *models.CollabMessage
+
+	cursor *models.CollabCursor // guarded by doc.mu
+}
+
+// collaborator must be called with doc.mu held.
+func (c *collabClient) collaborator() *models.Collaborator {
+	collaborator := &models.Collaborator{ClientID: c.clientID, User: c.user}
+	if c.cursor != nil {
+		cursor := *c.cursor
+		collaborator.Cursor = &cursor
+	}
+	return collaborator
+}
+
+func (c *collabClient) Messages() <-chan *models.CollabMessage {
+	return c.messages
+}
+
+// Apply transforms an edit made on revision against the edits made since,
+// applies it and sends it to the other collaborators. The sender gets an ack
+// with the new revision.
+func (c *collabClient) Apply(revision int, op models.TextOperation) error {
+	if err := validateOperation(op); err != nil {
+		return err
+	}
+	d := c.doc
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.clients[c]; !ok {
+		return errors.New("session closed")
+	}
+	if revision > d.revision || revision < d.revision-len(d.history) {
+		return errors.New("revision out of range")
+	}
+
+	var err error
+	for _, concurrent := range d.history[len(d.history)-(d.revision-revision):] {
+		if _, op, err = transformOperations(concurrent, op); err != nil {
+			return err
+		}
+	}
+	content, err := applyOperation(d.content, op)
+	if err != nil {
+		return err
+	}
+	if len(content) > maxCollabContent {
+		return errors.New("content is too long")
+	}
+
+	d.content = content
+	d.revision++
+	d.dirty = true
+	d.history = append(d.history, op)
+	if len(d.history) > c.service.historySize {
+		d.history = d.history[len(d.history)-c.service.historySize:]
+	}
+	for other := range d.clients {
+		if other != c && other.cursor != nil {
+			other.cursor.Position = transformPosition(op, other.cursor.Position)
+			other.cursor.SelectionEnd = transformPosition(op, other.cursor.SelectionEnd)
+		}
+	}
+
+	d.send(c, &models.CollabMessage{Type: models.CollabMessageAck, Revision: d.revision})
+	d.broadcast(c, &models.CollabMessage{
+		Type:         models.CollabMessageOperation,
+		Revision:     d.revision,
+		Operation:    op,
+		Collaborator: c.collaborator(),
+	})
+	return nil
+}
+
+// MoveCursor shares the client's cursor, given on the current revision.
+func (c *collabClient) MoveCursor(cursor models.CollabCursor) {
+	d := c.doc
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.clients[c]; !ok {
+		return
+	}
+	cursor.Position = max(0, min(cursor.Position, len(d.content)))
+	cursor.SelectionEnd = max(0, min(cursor.SelectionEnd, len(d.content)))
+	c.cursor = &cursor
+	d.broadcast(c, &models.CollabMessage{Type: models.CollabMessageCursor, Collaborator: c.collaborator()})
+}
+
+func (c *collabClient) Leave() {
+	c.service.leave(c)
+}
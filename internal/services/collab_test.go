@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/internal/repository"
+	"github.com/google/uuid"
+)
+
+// newTestCollab opens a draft by alice with the content "hello" and returns
+// a service keeping historySize revisions, alice and her co-author bob.
+func newTestCollab(t *testing.T, historySize int) (interfaces.CollabService, uuid.UUID, *models.User, *models.User) {
+	t.Helper()
+	ctx := context.Background()
+	db := openTestDB(t)
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	postRepo := repository.NewPostRepository(db)
+	post, err := postRepo.Create(ctx, &models.Post{Title: "Draft", Content: "hello", Status: models.PostStatusDraft, UserID: alice.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	coAuthorRepo := repository.NewCoAuthorRepository(db)
+	if err := coAuthorRepo.Add(ctx, post.ID, bob.ID); err != nil {
+		t.Fatal(err)
+	}
+	s := NewCollabService(postRepo, coAuthorRepo, repository.NewUserRepository(db), repository.NewTxManager(db), time.Hour, historySize, 64)
+	return s, post.ID, alice, bob
+}
+
+func joinCollab(t *testing.T, s interfaces.CollabService, userId, postId uuid.UUID) interfaces.CollabSession {
+	t.Helper()
+	session, err := s.Join(context.Background(), userId, postId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(session.Leave)
+	return session
+}
+
+// snapshot joins the document again and returns what a new collaborator sees.
+func snapshot(t *testing.T, s interfaces.CollabService, userId, postId uuid.UUID) *models.CollabMessage {
+	t.Helper()
+	session, err := s.Join(context.Background(), userId, postId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Leave()
+	return <-session.Messages()
+}
+
+func TestCollabConcurrentEdits(t *testing.T) {
+	s, postId, alice, bob := newTestCollab(t, 10)
+	a := joinCollab(t, s, alice.ID, postId)
+	b := joinCollab(t, s, bob.ID, postId)
+
+	// Both edit revision 0; bob's edit is transformed against alice's.
+	if err := a.Apply(0, models.TextOperation{{Insert: "oh, "}, {Retain: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Apply(0, models.TextOperation{{Retain: 5}, {Insert: "!"}}); err != nil {
+		t.Fatal(err)
+	}
+	got := snapshot(t, s, alice.ID, postId)
+	if got.Content != "oh, hello!" || got.Revision != 2 {
+		t.Errorf("document = %q at revision %d, want %q at revision 2", got.Content, got.Revision, "oh, hello!")
+	}
+}
+
+func TestCollabRevisionWindow(t *testing.T) {
+	s, postId, alice, bob := newTestCollab(t, 2)
+	a := joinCollab(t, s, alice.ID, postId)
+	b := joinCollab(t, s, bob.ID, postId)
+
+	for i := range 3 {
+		if err := a.Apply(i, models.TextOperation{{Retain: 5 + i}, {Insert: "!"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The document is at revision 3 and remembers the last two operations,
+	// so edits can be made on revisions 1 to 3.
+	tests := []struct {
+		revision int
+		ok       bool
+	}{
+		{-1, false},
+		{0, false},
+		{4, false},
+		{1, true},
+	}
+	for _, tt := range tests {
+		// On revision 1 the document was "hello!".
+		err := b.Apply(tt.revision, models.TextOperation{{Insert: ">"}, {Retain: 6}})
+		if (err == nil) != tt.ok {
+			t.Errorf("edit on revision %d: %v, want accepted %v", tt.revision, err, tt.ok)
+		}
+	}
+	got := snapshot(t, s, alice.ID, postId)
+	if got.Content != ">hello!!!" || got.Revision != 4 {
+		t.Errorf("document = %q at revision %d, want %q at revision 4", got.Content, got.Revision, ">hello!!!")
+	}
+}
+
+func TestCollabRejectsMismatchedEdits(t *testing.T) {
+	s, postId, alice, _ := newTestCollab(t, 10)
+	a := joinCollab(t, s, alice.ID, postId)
+
+	tests := []models.TextOperation{
+		{{Retain: 4}, {Insert: "!"}},
+		{{Retain: 6}},
+		{{Retain: 1, Delete: 1}, {Retain: 4}},
+	}
+	for _, op := range tests {
+		if err := a.Apply(0, op); err == nil {
+			t.Errorf("edit %v was accepted", op)
+		}
+	}
+	if got := snapshot(t, s, alice.ID, postId); got.Content != "hello" || got.Revision != 0 {
+		t.Errorf("document = %q at revision %d after rejected edits", got.Content, got.Revision)
+	}
+}
+
+func TestCollabCursorFollowsEdits(t *testing.T) {
+	s, postId, alice, bob := newTestCollab(t, 10)
+	a := joinCollab(t, s, alice.ID, postId)
+	b := joinCollab(t, s, bob.ID, postId)
+
+	// Bob selects "ll", then alice inserts text before it.
+	b.MoveCursor(models.CollabCursor{Position: 2, SelectionEnd: 4})
+	if err := a.Apply(0, models.TextOperation{{Insert: "oh, "}, {Retain: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	got := snapshot(t, s, alice.ID, postId)
+	for _, collaborator := range got.Collaborators {
+		if collaborator.User.ID != bob.ID {
+			continue
+		}
+		if cursor := collaborator.Cursor; cursor == nil || cursor.Position != 6 || cursor.SelectionEnd != 8 {
+			t.Errorf("bob's cursor = %+v, want 6-8", cursor)
+		}
+		return
+	}
+	t.Error("bob is missing from the collaborators")
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
@@ -9,66 +10,341 @@ import (
 )
 
 type commentService struct {
-	commentRepo interfaces.CommentRepository
+	commentRepo  interfaces.CommentRepository
+	postRepo     interfaces.PostRepository
+	reactionRepo interfaces.ReactionRepository
+	txManager    interfaces.TxManager
+	spamService  interfaces.SpamService
+	events       interfaces.EventPublisher
+	webhooks     interfaces.WebhookService
+	maxDepth     int
+	policy       string
 }
 
-func NewcommentService(commentRepo interfaces.CommentRepository) interfaces.CommentService {
+// NewcommentService creates the comment service. maxDepth limits how deeply
+// replies nest; top level comments have depth 0. policy is the moderation
+// policy for posts that don't set their own.
+func NewcommentService(commentRepo interfaces.CommentRepository, postRepo interfaces.PostRepository, reactionRepo interfaces.ReactionRepository, txManager interfaces.TxManager, spamService interfaces.SpamService, events interfaces.EventPublisher, webhooks interfaces.WebhookService, maxDepth int, policy string) interfaces.CommentService {
 	return &commentService{
-		commentRepo: commentRepo,
+		commentRepo:  commentRepo,
+		postRepo:     postRepo,
+		reactionRepo: reactionRepo,
+		txManager:    txManager,
+		spamService:  spamService,
+		events:       events,
+		webhooks:     webhooks,
+		maxDepth:     maxDepth,
+		policy:       policy,
 	}
 }
 
-func (s *commentService) CreateComment(userId uuid.UUID, comment *models.Comment) (*models.Comment, error) {
+// visiblePost returns the post when it exists and the viewer may see it.
+func (s *commentService) visiblePost(ctx context.Context, viewerId, postId uuid.UUID) (*models.Post, error) {
+	post, err := s.postRepo.GetByID(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	if !post.VisibleTo(viewerId) {
+		return nil, errors.New("post not found")
+	}
+	return post, nil
+}
+
+func (s *commentService) CreateComment(ctx context.Context, userId uuid.UUID, comment *models.Comment) (*models.Comment, error) {
+	post, err := s.visiblePost(ctx, userId, comment.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if post.CommentsClosed {
+		return nil, errors.New("comments are closed on this post")
+	}
+	if comment.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *comment.ParentID)
+		if err != nil {
+			return nil, errors.New("parent comment not found")
+		}
+		if parent.PostID != comment.PostID {
+			return nil, errors.New("parent comment belongs to another post")
+		}
+		if parent.Deleted {
+			return nil, errors.New("cannot reply to a deleted comment")
+		}
+		if parent.Status != models.CommentStatusApproved {
+			return nil, errors.New("parent comment not found")
+		}
+		if parent.Depth+1 > s.maxDepth {
+			return nil, errors.New("maximum reply depth reached")
+		}
+	}
 	comment.UserID = userId
-	comment, err := s.commentRepo.Create(comment)
+	comment.Status, err = s.initialStatus(ctx, userId, post)
 	if err != nil {
 		return nil, err
 	}
-	return comment, nil
+
+	report, err := s.spamService.Check(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+	comment.SpamScore = report.Score
+	switch report.Verdict {
+	case models.SpamVerdictSpam:
+		comment.Status = models.CommentStatusSpam
+	case models.SpamVerdictReview:
+		comment.Status = models.CommentStatusPending
+	}
+
+	var created *models.Comment
+	var event *models.Event
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.commentRepo.Create(ctx, comment)
+		if err != nil {
+			return err
+		}
+		if created.Status != models.CommentStatusApproved {
+			return nil
+		}
+		event = createdEvent(post, created)
+		return s.webhooks.Enqueue(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if event != nil {
+		s.events.Publish(ctx, event)
+	}
+	return created, nil
 }
 
-func (s *commentService) GetCommentByID(id uuid.UUID) (*models.Comment, error) {
-	comment, err := s.commentRepo.GetByID(id)
+// createdEvent announces a comment once other users can see it. Its webhook
+// deliveries are queued in the transaction that approves it and the event is
+// published after that commits.
+func createdEvent(post *models.Post, comment *models.Comment) *models.Event {
+	return &models.Event{
+		Type:    models.EventCommentCreated,
+		ActorID: comment.UserID,
+		Post:    post,
+		Comment: comment,
+	}
+}
+
+// initialStatus applies the post's moderation policy, or the site wide one.
+// Authors don't need approval on their own posts. Unknown policies hold the
+// comment for moderation.
+func (s *commentService) initialStatus(ctx context.Context, userId uuid.UUID, post *models.Post) (string, error) {
+	if post.UserID == userId {
+		return models.CommentStatusApproved, nil
+	}
+	policy := post.CommentPolicy
+	if policy == "" {
+		policy = s.policy
+	}
+	switch policy {
+	case models.CommentPolicyAuto:
+		return models.CommentStatusApproved, nil
+	case models.CommentPolicyFirstTime:
+		approved, err := s.commentRepo.HasApproved(ctx, userId)
+		if err != nil {
+			return "", err
+		}
+		if approved {
+			return models.CommentStatusApproved, nil
+		}
+	}
+	return models.CommentStatusPending, nil
+}
+
+func (s *commentService) GetCommentByID(ctx context.Context, viewerId, id uuid.UUID) (*models.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	post, err := s.postRepo.GetByID(ctx, comment.PostID)
+	if err != nil {
+		return nil, err
+	}
+	// A comment is hidden along with its post, so a draft's comments don't
+	// leak before it is published.
+	if !comment.VisibleTo(viewerId) || !post.VisibleTo(viewerId) {
+		return nil, errors.New("comment not found")
+	}
+	if err := attachCommentReactions(ctx, s.reactionRepo, viewerId, []*models.Comment{comment}); err != nil {
+		return nil, err
+	}
 	return comment, nil
 }
 
-func (s *commentService) GetAllComments() ([]*models.Comment, error) {
-	comments, err := s.commentRepo.GetAll()
+func (s *commentService) GetAllComments(ctx context.Context, opts models.ListOptions) ([]*models.Comment, string, error) {
+	comments, next, err := s.commentRepo.GetAll(ctx, opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	if err := attachCommentReactions(ctx, s.reactionRepo, opts.ViewerID, comments); err != nil {
+		return nil, "", err
 	}
-	return comments, nil
+	return comments, next, nil
 }
 
-func (s *commentService) UpdateComment(userId, commentId uuid.UUID, comment *models.Comment) (*models.Comment, error) {
-	commentCheck, err := s.commentRepo.GetByID(commentId)
+func (s *commentService) GetPostComments(ctx context.Context, viewerId, postId uuid.UUID, opts models.ListOptions) ([]*models.Comment, string, error) {
+	if _, err := s.visiblePost(ctx, viewerId, postId); err != nil {
+		return nil, "", err
+	}
+	opts.PostID = postId
+	opts.ViewerID = viewerId
+	comments, next, err := s.commentRepo.GetAll(ctx, opts)
 	if err != nil {
-		return nil, errors.New("comment not found")
+		return nil, "", err
 	}
-	if commentCheck.UserID != userId {
-		return nil, errors.New("unauthorized")
+	if err := attachCommentReactions(ctx, s.reactionRepo, viewerId, comments); err != nil {
+		return nil, "", err
 	}
+	return comments, next, nil
+}
 
-	comment, err = s.commentRepo.Update(commentId, comment)
+func (s *commentService) UpdateComment(ctx context.Context, userId, commentId uuid.UUID, comment *models.Comment) (*models.Comment, error) {
+	var updated *models.Comment
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		commentCheck, err := s.commentRepo.GetByID(ctx, commentId)
+		if err != nil {
+			return errors.New("comment not found")
+		}
+		if commentCheck.UserID != userId {
+			return errors.New("unauthorized")
+		}
+		updated, err = s.commentRepo.Update(ctx, commentId, comment)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return comment, nil
+	return updated, nil
+}
+
+func (s *commentService) DeleteComment(ctx context.Context, userId, commentId uuid.UUID) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		checkComment, err := s.commentRepo.GetByID(ctx, commentId)
+		if err != nil {
+			return err
+		}
+		if checkComment.UserID != userId {
+			return errors.New("unauthorized")
+		}
+		return s.deleteComment(ctx, checkComment)
+	})
 }
 
-func (s *commentService) DeleteComment(userId, commentId uuid.UUID) error {
-	checkComment, err := s.commentRepo.GetByID(commentId)
+// deleteComment keeps a "[deleted]" placeholder for comments that have
+// replies. Removing the last reply under a placeholder removes the
+// placeholder as well, all the way up the thread.
+func (s *commentService) deleteComment(ctx context.Context, comment *models.Comment) error {
+	hasReplies, err := s.commentRepo.HasReplies(ctx, comment.ID)
 	if err != nil {
 		return err
 	}
-	if checkComment.UserID != userId {
-		return errors.New("unauthorized")
+	if hasReplies {
+		if comment.Deleted {
+			return nil
+		}
+		return s.commentRepo.SoftDelete(ctx, comment.ID)
+	}
+	if err := s.commentRepo.Delete(ctx, comment.ID); err != nil {
+		return err
+	}
+	if comment.ParentID == nil {
+		return nil
 	}
-	if err := s.commentRepo.Delete(commentId); err != nil {
+	parent, err := s.commentRepo.GetByID(ctx, *comment.ParentID)
+	if err != nil {
 		return err
 	}
-	return nil
+	if !parent.Deleted {
+		return nil
+	}
+	return s.deleteComment(ctx, parent)
+}
+
+// GetModerationQueue lists the comments in a moderation state, oldest first
+// unless opts says otherwise.
+func (s *commentService) GetModerationQueue(ctx context.Context, status string, opts models.ListOptions) ([]*models.Comment, string, error) {
+	if status == "" {
+		status = models.CommentStatusPending
+	}
+	if !isCommentStatus(status) {
+		return nil, "", errors.New("invalid comment status")
+	}
+	if opts.Sort == "" {
+		opts.Sort = "createdAt"
+	}
+	return s.commentRepo.GetByStatus(ctx, status, opts)
+}
+
+// ModerateComments moves the comments to status and returns how many were
+// found. Marking comments as spam or approving them trains the spam
+// classifier. Comments approved for the first time are announced as new.
+func (s *commentService) ModerateComments(ctx context.Context, ids []uuid.UUID, status string) (int, error) {
+	if !isCommentStatus(status) {
+		return 0, errors.New("invalid comment status")
+	}
+	var updated int
+	var events []*models.Event
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var approved []*models.Comment
+		events = nil
+		if status == models.CommentStatusApproved {
+			for _, id := range ids {
+				comment, err := s.commentRepo.GetByID(ctx, id)
+				if err != nil || comment.Deleted || comment.ApprovedAt != nil {
+					continue
+				}
+				approved = append(approved, comment)
+			}
+		}
+
+		var err error
+		updated, err = s.commentRepo.SetStatus(ctx, ids, status)
+		if err != nil {
+			return err
+		}
+		for _, comment := range approved {
+			post, err := s.postRepo.GetByID(ctx, comment.PostID)
+			if err != nil {
+				continue
+			}
+			comment.Status = models.CommentStatusApproved
+			event := createdEvent(post, comment)
+			if err := s.webhooks.Enqueue(ctx, event); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		if status != models.CommentStatusSpam && status != models.CommentStatusApproved {
+			return nil
+		}
+		var samples []*models.Comment
+		for _, id := range ids {
+			comment, err := s.commentRepo.GetByID(ctx, id)
+			if err != nil || comment.Deleted {
+				continue
+			}
+			samples = append(samples, comment)
+		}
+		return s.spamService.Learn(ctx, samples, status == models.CommentStatusSpam)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		s.events.Publish(ctx, event)
+	}
+	return updated, nil
+}
+
+func isCommentStatus(status string) bool {
+	switch status {
+	case models.CommentStatusPending, models.CommentStatusApproved, models.CommentStatusRejected, models.CommentStatusSpam:
+		return true
+	}
+	return false
 }
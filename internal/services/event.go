@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+)
+
+type subscriber struct {
+	name    string
+	handler interfaces.EventHandler
+}
+
+type eventBus struct {
+	mu          sync.RWMutex
+	subscribers []subscriber
+}
+
+// NewEventBus delivers events to every subscriber in the background, so a
+// slow or failing subscriber never holds up or fails the request that caused
+// the event. Errors are logged.
+func NewEventBus() interfaces.EventBus {
+	return &eventBus{}
+}
+
+func (b *eventBus) Subscribe(name string, handler interfaces.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber{name: name, handler: handler})
+}
+
+// Publish must be called after the change is committed. The subscribers get
+// a context that outlives the request.
+func (b *eventBus) Publish(ctx context.Context, event *models.Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, s := range subscribers {
+		go func(s subscriber) {
+			if err := s.handler(ctx, event); err != nil {
+				utils.Log(utils.WARNING, "%s failed to handle %s event: %v", s.name, event.Type, err)
+			}
+		}(s)
+	}
+}
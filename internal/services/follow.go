@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type followService struct {
+	followRepo interfaces.FollowRepository
+	userRepo   interfaces.UserRepository
+	events     interfaces.EventPublisher
+}
+
+func NewFollowService(followRepo interfaces.FollowRepository, userRepo interfaces.UserRepository, events interfaces.EventPublisher) interfaces.FollowService {
+	return &followService{
+		followRepo: followRepo,
+		userRepo:   userRepo,
+		events:     events,
+	}
+}
+
+func (s *followService) Follow(ctx context.Context, userId, followeeId uuid.UUID) error {
+	if userId == followeeId {
+		return errors.New("you cannot follow yourself")
+	}
+	if _, err := s.userRepo.GetByID(ctx, followeeId); err != nil {
+		return err
+	}
+	created, err := s.followRepo.Follow(ctx, userId, followeeId)
+	if err != nil {
+		return err
+	}
+	if created {
+		s.events.Publish(ctx, &models.Event{Type: models.EventUserFollowed, ActorID: userId, UserID: followeeId})
+	}
+	return nil
+}
+
+func (s *followService) Unfollow(ctx context.Context, userId, followeeId uuid.UUID) error {
+	return s.followRepo.Unfollow(ctx, userId, followeeId)
+}
+
+func (s *followService) GetFollowers(ctx context.Context, userId uuid.UUID, opts models.ListOptions) ([]*models.Follow, string, error) {
+	if _, err := s.userRepo.GetByID(ctx, userId); err != nil {
+		return nil, "", err
+	}
+	return s.followRepo.GetFollowers(ctx, userId, opts)
+}
+
+func (s *followService) GetFollowing(ctx context.Context, userId uuid.UUID, opts models.ListOptions) ([]*models.Follow, string, error) {
+	if _, err := s.userRepo.GetByID(ctx, userId); err != nil {
+		return nil, "", err
+	}
+	return s.followRepo.GetFollowing(ctx, userId, opts)
+}
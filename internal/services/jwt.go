@@ -27,8 +27,9 @@ func NewJWTService(secretKey string, tokenExp, resetTokenExp, verificationTokenE
 
 func (s *jwtService) GenerateToken(user *models.User) (string, error) {
 	claims := jwt.MapClaims{
-		"user_id": user.ID.String(),
-		"exp":     time.Now().Add(s.tokenExpiration).Unix(),
+		"user_id":  user.ID.String(),
+		"username": user.Username,
+		"exp":      time.Now().Add(s.tokenExpiration).Unix(),
 	}
 	return s.CreateTokenWithClaims(claims)
 }
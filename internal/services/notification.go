@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+// notificationBatchSize bounds the IN lists used when a post is announced to
+// all of its author's followers.
+const notificationBatchSize = 500
+
+type notificationService struct {
+	notificationRepo interfaces.NotificationRepository
+	commentRepo      interfaces.CommentRepository
+	followRepo       interfaces.FollowRepository
+	userRepo         interfaces.UserRepository
+	txManager        interfaces.TxManager
+	events           interfaces.EventPublisher
+}
+
+// NewNotificationService turns domain events into notifications. Subscribe
+// HandleEvent to the event bus. Every stored notification is published in
+// turn, so it can be pushed to the recipient.
+func NewNotificationService(notificationRepo interfaces.NotificationRepository, commentRepo interfaces.CommentRepository, followRepo interfaces.FollowRepository, userRepo interfaces.UserRepository, txManager interfaces.TxManager, events interfaces.EventPublisher) interfaces.NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		commentRepo:      commentRepo,
+		followRepo:       followRepo,
+		userRepo:         userRepo,
+		txManager:        txManager,
+		events:           events,
+	}
+}
+
+func (s *notificationService) HandleEvent(ctx context.Context, event *models.Event) error {
+	switch event.Type {
+	case models.EventCommentCreated:
+		return s.commentCreated(ctx, event)
+	case models.EventPostPublished:
+		followers, err := s.followRepo.GetFollowerIDs(ctx, event.ActorID)
+		if err != nil {
+			return err
+		}
+		return s.notify(ctx, event, models.NotificationPost, followers)
+	case models.EventUserFollowed:
+		return s.notify(ctx, event, models.NotificationFollow, []uuid.UUID{event.UserID})
+	}
+	return nil
+}
+
+// commentCreated notifies the author of the comment replied to and the
+// author of the post. Someone who is both only gets the reply.
+func (s *notificationService) commentCreated(ctx context.Context, event *models.Event) error {
+	postAuthor := event.Post.UserID
+	if event.Comment.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *event.Comment.ParentID)
+		if err != nil {
+			return err
+		}
+		if !parent.Deleted {
+			if err := s.notify(ctx, event, models.NotificationReply, []uuid.UUID{parent.UserID}); err != nil {
+				return err
+			}
+			if parent.UserID == postAuthor {
+				return nil
+			}
+		}
+	}
+	return s.notify(ctx, event, models.NotificationComment, []uuid.UUID{postAuthor})
+}
+
+// notify stores a notification for each recipient who wants this type.
+// Actors are never notified about their own actions.
+func (s *notificationService) notify(ctx context.Context, event *models.Event, notificationType string, recipients []uuid.UUID) error {
+	recipients = slices.DeleteFunc(recipients, func(id uuid.UUID) bool {
+		return id == uuid.Nil || id == event.ActorID
+	})
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	notification := models.Notification{
+		Type:    notificationType,
+		ActorID: &event.ActorID,
+		Actor:   models.Author{DisplayName: models.DeletedAuthorName},
+	}
+	if actor, err := s.userRepo.GetByID(ctx, event.ActorID); err == nil {
+		notification.Actor = models.Author{
+			ID:          actor.ID,
+			Username:    actor.Username,
+			DisplayName: strings.TrimSpace(actor.FirstName + " " + actor.LastName),
+		}
+	}
+	if event.Post != nil {
+		notification.PostID = &event.Post.ID
+		notification.PostTitle = event.Post.Title
+	}
+	if event.Comment != nil {
+		notification.CommentID = &event.Comment.ID
+	}
+
+	for len(recipients) > 0 {
+		batch := recipients[:min(notificationBatchSize, len(recipients))]
+		recipients = recipients[len(batch):]
+
+		enabled, err := s.notificationRepo.FilterEnabled(ctx, notificationType, batch)
+		if err != nil {
+			return err
+		}
+		var created []*models.Notification
+		err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			for _, userId := range enabled {
+				n := notification
+				n.UserID = userId
+				if err := s.notificationRepo.Create(ctx, &n); err != nil {
+					return err
+				}
+				created = append(created, &n)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, n := range created {
+			s.events.Publish(ctx, &models.Event{
+				Type:         models.EventNotificationCreated,
+				ActorID:      event.ActorID,
+				UserID:       n.UserID,
+				Notification: n,
+			})
+		}
+	}
+	return nil
+}
+
+func (s *notificationService) GetNotifications(ctx context.Context, userId uuid.UUID, unreadOnly bool, opts models.ListOptions) ([]*models.Notification, string, error) {
+	return s.notificationRepo.GetByUserID(ctx, userId, unreadOnly, opts)
+}
+
+func (s *notificationService) CountUnread(ctx context.Context, userId uuid.UUID) (int, error) {
+	return s.notificationRepo.CountUnread(ctx, userId)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userId, id uuid.UUID) error {
+	return s.notificationRepo.MarkRead(ctx, userId, id)
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userId uuid.UUID) (int, error) {
+	return s.notificationRepo.MarkAllRead(ctx, userId)
+}
+
+// GetPreferences returns every notification type and whether the user
+// receives it.
+func (s *notificationService) GetPreferences(ctx context.Context, userId uuid.UUID) (map[string]bool, error) {
+	stored, err := s.notificationRepo.GetPreferences(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	preferences := make(map[string]bool, len(models.NotificationTypes))
+	for _, notificationType := range models.NotificationTypes {
+		enabled, ok := stored[notificationType]
+		preferences[notificationType] = enabled || !ok
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences changes the types in preferences and leaves the others
+// as they are.
+func (s *notificationService) UpdatePreferences(ctx context.Context, userId uuid.UUID, preferences map[string]bool) (map[string]bool, error) {
+	for notificationType := range preferences {
+		if !slices.Contains(models.NotificationTypes, notificationType) {
+			return nil, errors.New("invalid notification type: " + notificationType)
+		}
+	}
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		for notificationType, enabled := range preferences {
+			if err := s.notificationRepo.SetPreference(ctx, userId, notificationType, enabled); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, userId)
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+// The functions below implement operational transformation for plain text,
+// in the style of ot.js: every operation covers the whole document, so two
+// operations made on the same revision can be transformed against each other
+// and applied in either order with the same result.
+
+var errOperationMismatch = errors.New("operation does not match the document")
+
+// validateOperation checks that every component does exactly one thing.
+func validateOperation(op models.TextOperation) error {
+	for _, c := range op {
+		set := 0
+		if c.Retain != 0 {
+			set++
+		}
+		if c.Insert != "" {
+			set++
+		}
+		if c.Delete != 0 {
+			set++
+		}
+		if set != 1 || c.Retain < 0 || c.Delete < 0 {
+			return errors.New("invalid operation")
+		}
+	}
+	return nil
+}
+
+// applyOperation returns doc with op applied.
+func applyOperation(doc []rune, op models.TextOperation) ([]rune, error) {
+	result := make([]rune, 0, len(doc))
+	index := 0
+	for _, c := range op {
+		switch {
+		case c.Retain > 0:
+			if index+c.Retain > len(doc) {
+				return nil, errOperationMismatch
+			}
+			result = append(result, doc[index:index+c.Retain]...)
+			index += c.Retain
+		case c.Insert != "":
+			result = append(result, []rune(c.Insert)...)
+		case c.Delete > 0:
+			if index+c.Delete > len(doc) {
+				return nil, errOperationMismatch
+			}
+			index += c.Delete
+		}
+	}
+	if index != len(doc) {
+		return nil, errOperationMismatch
+	}
+	return result, nil
+}
+
+// transformOperations takes a and b made on the same document and returns a'
+// and b' such that applying a then b' gives the same document as b then a'.
+// When both insert at the same place, a's text comes first.
+func transformOperations(a, b models.TextOperation) (models.TextOperation, models.TextOperation, error) {
+	var aPrime, bPrime operationBuilder
+	i, j := 0, 0
+	var ca, cb models.TextComponent
+	nextA := func() {
+		ca = models.TextComponent{}
+		if i < len(a) {
+			ca = a[i]
+			i++
+		}
+	}
+	nextB := func() {
+		cb = models.TextComponent{}
+		if j < len(b) {
+			cb = b[j]
+			j++
+		}
+	}
+	nextA()
+	nextB()
+
+	for ca != (models.TextComponent{}) || cb != (models.TextComponent{}) {
+		if ca.Insert != "" {
+			aPrime.insert(ca.Insert)
+			bPrime.retain(utf8.RuneCountInString(ca.Insert))
+			nextA()
+			continue
+		}
+		if cb.Insert != "" {
+			aPrime.retain(utf8.RuneCountInString(cb.Insert))
+			bPrime.insert(cb.Insert)
+			nextB()
+			continue
+		}
+		if ca == (models.TextComponent{}) || cb == (models.TextComponent{}) {
+			return nil, nil, errOperationMismatch
+		}
+
+		n := min(ca.Retain+ca.Delete, cb.Retain+cb.Delete)
+		switch {
+		case ca.Retain > 0 && cb.Retain > 0:
+			aPrime.retain(n)
+			bPrime.retain(n)
+		case ca.Delete > 0 && cb.Retain > 0:
+			aPrime.delete(n)
+		case ca.Retain > 0 && cb.Delete > 0:
+			bPrime.delete(n)
+		}
+		// Text both operations delete is simply gone.
+
+		if ca = consume(ca, n); ca == (models.TextComponent{}) {
+			nextA()
+		}
+		if cb = consume(cb, n); cb == (models.TextComponent{}) {
+			nextB()
+		}
+	}
+	return aPrime.op, bPrime.op, nil
+}
+
+// consume shortens a retain or delete by n.
+func consume(c models.TextComponent, n int) models.TextComponent {
+	if c.Retain > 0 {
+		c.Retain -= n
+	} else {
+		c.Delete -= n
+	}
+	return c
+}
+
+// transformPosition moves a position in the document to where the same text
+// is after op. Text inserted right at the position pushes it forward.
+func transformPosition(op models.TextOperation, position int) int {
+	index, result := 0, position
+	for _, c := range op {
+		if index > position {
+			break
+		}
+		switch {
+		case c.Retain > 0:
+			index += c.Retain
+		case c.Insert != "":
+			result += utf8.RuneCountInString(c.Insert)
+		case c.Delete > 0:
+			result -= min(c.Delete, position-index)
+			index += c.Delete
+		}
+	}
+	return result
+}
+
+// operationBuilder appends components, merging neighbours of the same kind.
+type operationBuilder struct {
+	op models.TextOperation
+}
+
+func (b *operationBuilder) retain(n int) {
+	if last := b.last(); last != nil && last.Retain > 0 {
+		last.Retain += n
+		return
+	}
+	b.op = append(b.op, models.TextComponent{Retain: n})
+}
+
+func (b *operationBuilder) insert(s string) {
+	if last := b.last(); last != nil && last.Insert != "" {
+		last.Insert += s
+		return
+	}
+	b.op = append(b.op, models.TextComponent{Insert: s})
+}
+
+func (b *operationBuilder) delete(n int) {
+	if last := b.last(); last != nil && last.Delete > 0 {
+		last.Delete += n
+		return
+	}
+	b.op = append(b.op, models.TextComponent{Delete: n})
+}
+
+func (b *operationBuilder) last() *models.TextComponent {
+	if len(b.op) == 0 {
+		return nil
+	}
+	return &b.op[len(b.op)-1]
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+func TestValidateOperation(t *testing.T) {
+	tests := []struct {
+		op    models.TextOperation
+		valid bool
+	}{
+		{models.TextOperation{{Retain: 2}, {Insert: "x"}, {Delete: 1}}, true},
+		{nil, true},
+		{models.TextOperation{{}}, false},
+		{models.TextOperation{{Retain: 1, Insert: "x"}}, false},
+		{models.TextOperation{{Retain: -1}}, false},
+		{models.TextOperation{{Delete: -1}}, false},
+	}
+	for _, tt := range tests {
+		if err := validateOperation(tt.op); (err == nil) != tt.valid {
+			t.Errorf("validateOperation(%v) = %v, want valid %v", tt.op, err, tt.valid)
+		}
+	}
+}
+
+func TestApplyOperation(t *testing.T) {
+	tests := []struct {
+		doc  string
+		op   models.TextOperation
+		want string
+		err  error
+	}{
+		{"hello", models.TextOperation{{Retain: 5}, {Insert: " world"}}, "hello world", nil},
+		{"hello", models.TextOperation{{Delete: 1}, {Insert: "j"}, {Retain: 4}}, "jello", nil},
+		{"çay ☕", models.TextOperation{{Retain: 4}, {Delete: 1}, {Insert: "🍵"}}, "çay 🍵", nil},
+		{"", models.TextOperation{{Insert: "new"}}, "new", nil},
+		{"hello", models.TextOperation{{Retain: 4}}, "", errOperationMismatch},
+		{"hello", models.TextOperation{{Retain: 6}}, "", errOperationMismatch},
+		{"hello", models.TextOperation{{Retain: 3}, {Delete: 3}}, "", errOperationMismatch},
+	}
+	for _, tt := range tests {
+		got, err := applyOperation([]rune(tt.doc), tt.op)
+		if !errors.Is(err, tt.err) || string(got) != tt.want {
+			t.Errorf("applyOperation(%q, %v) = %q, %v, want %q, %v", tt.doc, tt.op, string(got), err, tt.want, tt.err)
+		}
+	}
+}
+
+// TestTransformConvergence applies a then b' and b then a' and expects both
+// orders to end up with the same document.
+func TestTransformConvergence(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		a, b models.TextOperation
+		want string
+	}{
+		{
+			name: "inserts in different places",
+			doc:  "hello world",
+			a:    models.TextOperation{{Retain: 6}, {Insert: "big "}, {Retain: 5}},
+			b:    models.TextOperation{{Retain: 11}, {Insert: "!"}},
+			want: "hello big world!",
+		},
+		{
+			name: "inserts in the same place",
+			doc:  "hello world",
+			a:    models.TextOperation{{Retain: 5}, {Insert: "A"}, {Retain: 6}},
+			b:    models.TextOperation{{Retain: 5}, {Insert: "B"}, {Retain: 6}},
+			want: "helloAB world",
+		},
+		{
+			name: "insert inside deleted text",
+			doc:  "hello world",
+			a:    models.TextOperation{{Delete: 6}, {Retain: 5}},
+			b:    models.TextOperation{{Retain: 3}, {Insert: "X"}, {Retain: 8}},
+			want: "Xworld",
+		},
+		{
+			name: "overlapping deletes",
+			doc:  "hello world",
+			a:    models.TextOperation{{Retain: 2}, {Delete: 5}, {Retain: 4}},
+			b:    models.TextOperation{{Retain: 4}, {Delete: 5}, {Retain: 2}},
+			want: "held",
+		},
+		{
+			name: "same delete",
+			doc:  "hello world",
+			a:    models.TextOperation{{Retain: 5}, {Delete: 6}},
+			b:    models.TextOperation{{Retain: 5}, {Delete: 6}},
+			want: "hello",
+		},
+		{
+			name: "delete everything while appending",
+			doc:  "hello world",
+			a:    models.TextOperation{{Delete: 11}},
+			b:    models.TextOperation{{Retain: 11}, {Insert: "!"}},
+			want: "!",
+		},
+		{
+			name: "multi-byte text",
+			doc:  "çay ☕",
+			a:    models.TextOperation{{Retain: 4}, {Insert: "🍵"}, {Retain: 1}},
+			b:    models.TextOperation{{Delete: 1}, {Insert: "Ç"}, {Retain: 4}},
+			want: "Çay 🍵☕",
+		},
+		{
+			name: "nothing changes",
+			doc:  "hello",
+			a:    models.TextOperation{{Retain: 5}},
+			b:    models.TextOperation{{Retain: 5}},
+			want: "hello",
+		},
+		{
+			name: "empty document",
+			doc:  "",
+			a:    models.TextOperation{{Insert: "a"}},
+			b:    models.TextOperation{{Insert: "b"}},
+			want: "ab",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aPrime, bPrime, err := transformOperations(tt.a, tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			afterA, err := applyOperation([]rune(tt.doc), tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ab, err := applyOperation(afterA, bPrime)
+			if err != nil {
+				t.Fatalf("b' doesn't apply after a: %v", err)
+			}
+			afterB, err := applyOperation([]rune(tt.doc), tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ba, err := applyOperation(afterB, aPrime)
+			if err != nil {
+				t.Fatalf("a' doesn't apply after b: %v", err)
+			}
+			if string(ab) != tt.want || string(ba) != tt.want {
+				t.Errorf("a then b' = %q, b then a' = %q, want %q", string(ab), string(ba), tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformMismatchedOperations(t *testing.T) {
+	a := models.TextOperation{{Retain: 5}}
+	b := models.TextOperation{{Retain: 4}}
+	if _, _, err := transformOperations(a, b); !errors.Is(err, errOperationMismatch) {
+		t.Errorf("transforming operations on different documents = %v, want %v", err, errOperationMismatch)
+	}
+}
+
+func TestTransformPosition(t *testing.T) {
+	insert := models.TextOperation{{Retain: 3}, {Insert: "ab"}, {Retain: 5}}
+	remove := models.TextOperation{{Retain: 2}, {Delete: 3}, {Retain: 3}}
+	tests := []struct {
+		name     string
+		op       models.TextOperation
+		position int
+		want     int
+	}{
+		{"before an insert", insert, 2, 2},
+		{"at an insert", insert, 3, 5},
+		{"after an insert", insert, 4, 6},
+		{"at the end after an insert", insert, 8, 10},
+		{"before a delete", remove, 1, 1},
+		{"at a delete", remove, 2, 2},
+		{"inside a delete", remove, 3, 2},
+		{"at the end of a delete", remove, 5, 2},
+		{"after a delete", remove, 6, 3},
+		{"insert at the start", models.TextOperation{{Insert: "x"}, {Retain: 3}}, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := transformPosition(tt.op, tt.position); got != tt.want {
+			t.Errorf("%s: transformPosition(%d) = %d, want %d", tt.name, tt.position, got, tt.want)
+		}
+	}
+}
@@ -1,74 +1,263 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"slices"
 
 	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
 	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
 	"github.com/google/uuid"
 )
 
 type postService struct {
-	postRepo interfaces.PostRepository
+	postRepo     interfaces.PostRepository
+	commentRepo  interfaces.CommentRepository
+	reactionRepo interfaces.ReactionRepository
+	bookmarkRepo interfaces.BookmarkRepository
+	txManager    interfaces.TxManager
+	events       interfaces.EventPublisher
+	webhooks     interfaces.WebhookService
 }
 
-func NewPostService(postRepo interfaces.PostRepository) interfaces.PostService {
+func NewPostService(postRepo interfaces.PostRepository, commentRepo interfaces.CommentRepository, reactionRepo interfaces.ReactionRepository, bookmarkRepo interfaces.BookmarkRepository, txManager interfaces.TxManager, events interfaces.EventPublisher, webhooks interfaces.WebhookService) interfaces.PostService {
 	return &postService{
-		postRepo: postRepo,
+		postRepo:     postRepo,
+		commentRepo:  commentRepo,
+		reactionRepo: reactionRepo,
+		bookmarkRepo: bookmarkRepo,
+		txManager:    txManager,
+		events:       events,
+		webhooks:     webhooks,
 	}
 }
 
-func (s *postService) CreatePost(userId uuid.UUID, post *models.Post) (*models.Post, error) {
+func (s *postService) CreatePost(ctx context.Context, userId uuid.UUID, post *models.Post) (*models.Post, error) {
 	post.UserID = userId
-	post, err := s.postRepo.Create(post)
+	html, err := utils.RenderMarkdown(post.Content)
 	if err != nil {
 		return nil, err
 	}
+	post.ContentHTML = html
+	post.Tags = normalizeTags(post.Tags)
+	var event *models.Event
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		post, err = s.postRepo.Create(ctx, post)
+		if err != nil {
+			return err
+		}
+		if post.Status == models.PostStatusPublished {
+			event = &models.Event{Type: models.EventPostPublished, ActorID: userId, Post: post}
+		}
+		return s.record(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, event)
 	return post, nil
 }
 
-func (s *postService) GetPostByID(id uuid.UUID) (*models.Post, error) {
-	post, err := s.postRepo.GetByID(id)
+// normalizeTags normalizes tags and drops empty and repeated ones.
+func normalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = models.NormalizeTag(tag)
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// record queues the webhook deliveries for event in the transaction of the
+// change, so they are stored only if it commits.
+func (s *postService) record(ctx context.Context, event *models.Event) error {
+	if event == nil {
+		return nil
+	}
+	return s.webhooks.Enqueue(ctx, event)
+}
+
+// publish announces event once the change is committed.
+func (s *postService) publish(ctx context.Context, event *models.Event) {
+	if event != nil {
+		s.events.Publish(ctx, event)
+	}
+}
+
+func (s *postService) GetPostByID(ctx context.Context, viewerId, id uuid.UUID) (*models.Post, error) {
+	post, err := s.postRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	return post, nil
-}
+	if !post.VisibleTo(viewerId) {
+		return nil, errors.New("post not found")
+	}
 
-func (s *postService) GetAllPosts() ([]*models.Post, error) {
-	posts, err := s.postRepo.GetAll()
+	counts, err := s.commentRepo.CountByPostIDs(ctx, []uuid.UUID{post.ID})
 	if err != nil {
 		return nil, err
 	}
-	return posts, nil
+	post.CommentCount = counts[post.ID]
+	post.Comments, post.CommentsNext, err = s.commentRepo.GetAll(ctx, models.ListOptions{
+		PostID:   post.ID,
+		ViewerID: viewerId,
+		Sort:     "thread",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := attachPostReactions(ctx, s.reactionRepo, viewerId, []*models.Post{post}); err != nil {
+		return nil, err
+	}
+	if err := attachBookmarks(ctx, s.bookmarkRepo, viewerId, []*models.Post{post}); err != nil {
+		return nil, err
+	}
+	if err := attachCommentReactions(ctx, s.reactionRepo, viewerId, post.Comments); err != nil {
+		return nil, err
+	}
+	return post, nil
 }
 
-func (s *postService) UpdatePost(userId, postId uuid.UUID, post *models.Post) (*models.Post, error) {
-	postCheck, err := s.postRepo.GetByID(postId)
+func (s *postService) GetAllPosts(ctx context.Context, opts models.ListOptions) ([]*models.Post, string, error) {
+	posts, next, err := s.postRepo.GetAll(ctx, opts)
 	if err != nil {
-		return nil, errors.New("post not found")
+		return nil, "", err
 	}
-	if postCheck.UserID != userId {
-		return nil, errors.New("unauthorized user")
+	if err := s.enrich(ctx, opts.ViewerID, posts); err != nil {
+		return nil, "", err
 	}
+	return posts, next, nil
+}
 
-	post, err = s.postRepo.Update(postId, post)
+// ListPosts pages through posts like GetAllPosts but leaves out comment
+// counts, reactions and bookmarks, for callers such as the syndication feeds
+// that only show the posts themselves.
+func (s *postService) ListPosts(ctx context.Context, opts models.ListOptions) ([]*models.Post, string, error) {
+	return s.postRepo.GetAll(ctx, opts)
+}
+
+// GetFeed pages through the published posts of the authors the user follows,
+// newest first.
+func (s *postService) GetFeed(ctx context.Context, userId uuid.UUID, opts models.ListOptions) ([]*models.Post, string, error) {
+	posts, next, err := s.postRepo.GetFeed(ctx, userId, opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return post, nil
+	if err := s.enrich(ctx, userId, posts); err != nil {
+		return nil, "", err
+	}
+	return posts, next, nil
 }
 
-func (s *postService) DeletePost(userId, postId uuid.UUID) error {
-	checkPost, err := s.postRepo.GetByID(postId)
+// enrich adds comment counts, reactions and bookmarks to a page of posts.
+func (s *postService) enrich(ctx context.Context, viewerId uuid.UUID, posts []*models.Post) error {
+	ids := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	counts, err := s.commentRepo.CountByPostIDs(ctx, ids)
 	if err != nil {
 		return err
 	}
-	if checkPost.UserID != userId {
-		return errors.New("unauthorized")
+	for _, post := range posts {
+		post.CommentCount = counts[post.ID]
+	}
+	if err := attachPostReactions(ctx, s.reactionRepo, viewerId, posts); err != nil {
+		return err
+	}
+	return attachBookmarks(ctx, s.bookmarkRepo, viewerId, posts)
+}
+
+func (s *postService) UpdatePost(ctx context.Context, userId, postId uuid.UUID, post *models.Post) (*models.Post, error) {
+	html, err := utils.RenderMarkdown(post.Content)
+	if err != nil {
+		return nil, err
+	}
+	post.ContentHTML = html
+	if post.Tags != nil {
+		post.Tags = normalizeTags(post.Tags)
 	}
-	if err := s.postRepo.Delete(postId); err != nil {
+
+	var updated *models.Post
+	var event *models.Event
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		postCheck, err := s.postRepo.GetByID(ctx, postId)
+		if err != nil {
+			return errors.New("post not found")
+		}
+		if postCheck.UserID != userId {
+			return errors.New("unauthorized user")
+		}
+		wasDraft := postCheck.Status == models.PostStatusDraft
+		updated, err = s.postRepo.Update(ctx, postId, post)
+		if err != nil {
+			return err
+		}
+		switch {
+		case updated.Status == models.PostStatusPublished && wasDraft:
+			event = &models.Event{Type: models.EventPostPublished, ActorID: userId, Post: updated}
+		case updated.Status == models.PostStatusPublished:
+			event = &models.Event{Type: models.EventPostUpdated, ActorID: userId, Post: updated}
+		case !wasDraft:
+			event = &models.Event{Type: models.EventPostUnpublished, ActorID: userId, Post: updated}
+		}
+		return s.record(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, event)
+	return updated, nil
+}
+
+// UpdateCommentSettings lets the author of a post close its comments or
+// choose how new comments on it are moderated.
+func (s *postService) UpdateCommentSettings(ctx context.Context, userId, postId uuid.UUID, policy string, closed bool) (*models.Post, error) {
+	var updated *models.Post
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		postCheck, err := s.postRepo.GetByID(ctx, postId)
+		if err != nil {
+			return err
+		}
+		if postCheck.UserID != userId {
+			return errors.New("unauthorized user")
+		}
+		if err := s.postRepo.UpdateCommentSettings(ctx, postId, policy, closed); err != nil {
+			return err
+		}
+		updated, err = s.postRepo.GetByID(ctx, postId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *postService) DeletePost(ctx context.Context, userId, postId uuid.UUID) error {
+	var event *models.Event
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		deleted, err := s.postRepo.GetByID(ctx, postId)
+		if err != nil {
+			return err
+		}
+		if deleted.UserID != userId {
+			return errors.New("unauthorized")
+		}
+		if err := s.postRepo.Delete(ctx, postId); err != nil {
+			return err
+		}
+		if deleted.Status == models.PostStatusPublished {
+			event = &models.Event{Type: models.EventPostDeleted, ActorID: userId, Post: deleted}
+		}
+		return s.record(ctx, event)
+	})
+	if err != nil {
 		return err
 	}
+	s.publish(ctx, event)
 	return nil
 }
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type reactionService struct {
+	reactionRepo interfaces.ReactionRepository
+	postRepo     interfaces.PostRepository
+	commentRepo  interfaces.CommentRepository
+	events       interfaces.EventPublisher
+	types        []string
+}
+
+// NewReactionService accepts likes and the reaction types in types.
+func NewReactionService(reactionRepo interfaces.ReactionRepository, postRepo interfaces.PostRepository, commentRepo interfaces.CommentRepository, events interfaces.EventPublisher, types []string) interfaces.ReactionService {
+	allowed := []string{models.ReactionLike}
+	for _, reaction := range types {
+		if !slices.Contains(allowed, reaction) {
+			allowed = append(allowed, reaction)
+		}
+	}
+	return &reactionService{
+		reactionRepo: reactionRepo,
+		postRepo:     postRepo,
+		commentRepo:  commentRepo,
+		events:       events,
+		types:        allowed,
+	}
+}
+
+func (s *reactionService) React(ctx context.Context, userId uuid.UUID, target string, targetId uuid.UUID, reaction string) (*models.Reactions, error) {
+	post, comment, err := s.checkTarget(ctx, userId, target, targetId, reaction)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reactionRepo.Add(ctx, target, targetId, userId, reaction); err != nil {
+		return nil, err
+	}
+	return s.changed(ctx, userId, target, targetId, post, comment)
+}
+
+func (s *reactionService) Unreact(ctx context.Context, userId uuid.UUID, target string, targetId uuid.UUID, reaction string) (*models.Reactions, error) {
+	post, comment, err := s.checkTarget(ctx, userId, target, targetId, reaction)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reactionRepo.Remove(ctx, target, targetId, userId, reaction); err != nil {
+		return nil, err
+	}
+	return s.changed(ctx, userId, target, targetId, post, comment)
+}
+
+// checkTarget makes sure the reaction type is allowed and the user can see
+// what they react to. It returns the post, and the comment when reacting to
+// one.
+func (s *reactionService) checkTarget(ctx context.Context, userId uuid.UUID, target string, targetId uuid.UUID, reaction string) (*models.Post, *models.Comment, error) {
+	if !slices.Contains(s.types, reaction) {
+		return nil, nil, errors.New("invalid reaction type")
+	}
+	postId := targetId
+	var comment *models.Comment
+	if target == models.ReactionTargetComment {
+		var err error
+		comment, err = s.commentRepo.GetByID(ctx, targetId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if comment.Deleted || !comment.VisibleTo(userId) {
+			return nil, nil, errors.New("comment not found")
+		}
+		postId = comment.PostID
+	}
+	post, err := s.postRepo.GetByID(ctx, postId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !post.VisibleTo(userId) {
+		return nil, nil, errors.New("post not found")
+	}
+	return post, comment, nil
+}
+
+// changed returns the new reactions of the target and announces the new
+// counts.
+func (s *reactionService) changed(ctx context.Context, userId uuid.UUID, target string, targetId uuid.UUID, post *models.Post, comment *models.Comment) (*models.Reactions, error) {
+	reactions, err := s.summary(ctx, userId, target, targetId)
+	if err != nil {
+		return nil, err
+	}
+	s.events.Publish(ctx, &models.Event{
+		Type:      models.EventReactionChanged,
+		ActorID:   userId,
+		Post:      post,
+		Comment:   comment,
+		Reactions: &models.Reactions{Counts: reactions.Counts},
+	})
+	return reactions, nil
+}
+
+func (s *reactionService) summary(ctx context.Context, userId uuid.UUID, target string, targetId uuid.UUID) (*models.Reactions, error) {
+	counts, mine, err := loadReactions(ctx, s.reactionRepo, target, userId, []uuid.UUID{targetId})
+	if err != nil {
+		return nil, err
+	}
+	return &models.Reactions{Counts: counts[targetId], Mine: mine[targetId]}, nil
+}
+
+func loadReactions(ctx context.Context, reactionRepo interfaces.ReactionRepository, target string, viewerId uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]map[string]int, map[uuid.UUID][]string, error) {
+	counts, err := reactionRepo.GetCounts(ctx, target, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	mine, err := reactionRepo.GetUserReactions(ctx, target, viewerId, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return counts, mine, nil
+}
+
+// attachPostReactions fills in the reactions of the posts with two queries.
+func attachPostReactions(ctx context.Context, reactionRepo interfaces.ReactionRepository, viewerId uuid.UUID, posts []*models.Post) error {
+	ids := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	counts, mine, err := loadReactions(ctx, reactionRepo, models.ReactionTargetPost, viewerId, ids)
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		post.Reactions = models.Reactions{Counts: counts[post.ID], Mine: mine[post.ID]}
+	}
+	return nil
+}
+
+// attachCommentReactions fills in the reactions of the comments with two
+// queries.
+func attachCommentReactions(ctx context.Context, reactionRepo interfaces.ReactionRepository, viewerId uuid.UUID, comments []*models.Comment) error {
+	ids := make([]uuid.UUID, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+	counts, mine, err := loadReactions(ctx, reactionRepo, models.ReactionTargetComment, viewerId, ids)
+	if err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		comment.Reactions = models.Reactions{Counts: counts[comment.ID], Mine: mine[comment.ID]}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+type readingListService struct {
+	listRepo    interfaces.ReadingListRepository
+	postRepo    interfaces.PostRepository
+	postService interfaces.PostService
+	txManager   interfaces.TxManager
+}
+
+func NewReadingListService(listRepo interfaces.ReadingListRepository, postRepo interfaces.PostRepository, postService interfaces.PostService, txManager interfaces.TxManager) interfaces.ReadingListService {
+	return &readingListService{
+		listRepo:    listRepo,
+		postRepo:    postRepo,
+		postService: postService,
+		txManager:   txManager,
+	}
+}
+
+func (s *readingListService) CreateList(ctx context.Context, userId uuid.UUID, list *models.ReadingList) (*models.ReadingList, error) {
+	list.UserID = userId
+	var created *models.ReadingList
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkName(ctx, userId, uuid.Nil, list.Name); err != nil {
+			return err
+		}
+		var err error
+		created, err = s.listRepo.Create(ctx, list)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// checkName makes sure none of the user's other lists is called name.
+func (s *readingListService) checkName(ctx context.Context, userId, listId uuid.UUID, name string) error {
+	lists, err := s.listRepo.GetByUserID(ctx, userId, false)
+	if err != nil {
+		return err
+	}
+	for _, list := range lists {
+		if list.Name == name && list.ID != listId {
+			return errors.New("a reading list with this name already exists")
+		}
+	}
+	return nil
+}
+
+func (s *readingListService) GetList(ctx context.Context, viewerId, id uuid.UUID) (*models.ReadingList, error) {
+	list, err := s.listRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !list.VisibleTo(viewerId) {
+		return nil, errors.New("reading list not found")
+	}
+	return list, nil
+}
+
+// GetUserLists returns all of a user's lists to the user and only the public
+// ones to everybody else.
+func (s *readingListService) GetUserLists(ctx context.Context, viewerId, userId uuid.UUID) ([]*models.ReadingList, error) {
+	return s.listRepo.GetByUserID(ctx, userId, viewerId != userId)
+}
+
+// ownList returns the list when it belongs to the user. Other users' lists
+// are reported as missing rather than forbidden, so private lists stay hidden.
+func (s *readingListService) ownList(ctx context.Context, userId, id uuid.UUID) (*models.ReadingList, error) {
+	list, err := s.listRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != userId {
+		return nil, errors.New("reading list not found")
+	}
+	return list, nil
+}
+
+func (s *readingListService) UpdateList(ctx context.Context, userId, id uuid.UUID, list *models.ReadingList) (*models.ReadingList, error) {
+	var updated *models.ReadingList
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.ownList(ctx, userId, id); err != nil {
+			return err
+		}
+		if err := s.checkName(ctx, userId, id, list.Name); err != nil {
+			return err
+		}
+		var err error
+		updated, err = s.listRepo.Update(ctx, id, list)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *readingListService) DeleteList(ctx context.Context, userId, id uuid.UUID) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.ownList(ctx, userId, id); err != nil {
+			return err
+		}
+		return s.listRepo.Delete(ctx, id)
+	})
+}
+
+func (s *readingListService) AddPost(ctx context.Context, userId, listId, postId uuid.UUID) error {
+	if _, err := s.ownList(ctx, userId, listId); err != nil {
+		return err
+	}
+	post, err := s.postRepo.GetByID(ctx, postId)
+	if err != nil {
+		return err
+	}
+	if !post.VisibleTo(userId) {
+		return errors.New("post not found")
+	}
+	return s.listRepo.AddPost(ctx, listId, postId)
+}
+
+func (s *readingListService) RemovePost(ctx context.Context, userId, listId, postId uuid.UUID) error {
+	if _, err := s.ownList(ctx, userId, listId); err != nil {
+		return err
+	}
+	return s.listRepo.RemovePost(ctx, listId, postId)
+}
+
+// GetListPosts pages through the posts on a list that the viewer can see.
+func (s *readingListService) GetListPosts(ctx context.Context, viewerId, listId uuid.UUID, opts models.ListOptions) ([]*models.Post, string, error) {
+	if _, err := s.GetList(ctx, viewerId, listId); err != nil {
+		return nil, "", err
+	}
+	opts.ListID = listId
+	opts.ViewerID = viewerId
+	return s.postService.GetAllPosts(ctx, opts)
+}
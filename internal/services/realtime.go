@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+type hubSubscriber struct {
+	messages chan *models.RealtimeMessage
+	topics   []string
+	closed   bool
+}
+
+type realtimeHub struct {
+	mu         sync.Mutex
+	topics     map[string]map[*hubSubscriber]struct{}
+	bufferSize int
+	redis      *redis.Client
+	channel    string
+}
+
+// NewRealtimeHub fans messages out to subscribers in this process. Each
+// subscriber buffers bufferSize messages; one that falls further behind is
+// dropped rather than slowing everyone else down. With a Redis channel,
+// messages go through Redis pub/sub so subscribers on every instance get
+// them.
+func NewRealtimeHub(bufferSize int, redisAddr, channel string) interfaces.RealtimeHub {
+	h := &realtimeHub{
+		topics:     make(map[string]map[*hubSubscriber]struct{}),
+		bufferSize: bufferSize,
+	}
+	if channel != "" {
+		h.redis = redis.NewClient(&redis.Options{Addr: redisAddr})
+		h.channel = channel
+		go h.listen()
+	}
+	return h
+}
+
+func (h *realtimeHub) Publish(ctx context.Context, message *models.RealtimeMessage) error {
+	if h.redis == nil {
+		h.deliver(message)
+		return nil
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if err := h.redis.Publish(ctx, h.channel, payload).Err(); err != nil {
+		// Local subscribers still get the message while Redis is down.
+		h.deliver(message)
+		return err
+	}
+	return nil
+}
+
+// listen delivers the messages every instance publishes to Redis. The
+// subscription reconnects on its own after network errors.
+func (h *realtimeHub) listen() {
+	pubsub := h.redis.Subscribe(context.Background(), h.channel)
+	for payload := range pubsub.Channel() {
+		var message models.RealtimeMessage
+		if err := json.Unmarshal([]byte(payload.Payload), &message); err != nil {
+			utils.Log(utils.WARNING, "Invalid realtime message on %s: %v", h.channel, err)
+			continue
+		}
+		h.deliver(&message)
+	}
+}
+
+func (h *realtimeHub) deliver(message *models.RealtimeMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.topics[message.Topic] {
+		select {
+		case s.messages <- message:
+		default:
+			h.remove(s)
+		}
+	}
+}
+
+func (h *realtimeHub) Subscribe(topics ...string) (<-chan *models.RealtimeMessage, func()) {
+	s := &hubSubscriber{
+		messages: make(chan *models.RealtimeMessage, h.bufferSize),
+		topics:   topics,
+	}
+	h.mu.Lock()
+	for _, topic := range topics {
+		if h.topics[topic] == nil {
+			h.topics[topic] = make(map[*hubSubscriber]struct{})
+		}
+		h.topics[topic][s] = struct{}{}
+	}
+	h.mu.Unlock()
+
+	return s.messages, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(s)
+	}
+}
+
+// remove must be called with h.mu held.
+func (h *realtimeHub) remove(s *hubSubscriber) {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	for _, topic := range s.topics {
+		delete(h.topics[topic], s)
+		if len(h.topics[topic]) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+	close(s.messages)
+}
@@ -21,13 +21,11 @@ func NewRedisService(addr, password string, db int) interfaces.RedisService {
 	return &redisService{client: client}
 }
 
-func (s *redisService) BlacklistToken(token string, expiration time.Duration) error {
-	ctx := context.Background()
+func (s *redisService) BlacklistToken(ctx context.Context, token string, expiration time.Duration) error {
 	return s.client.Set(ctx, token, true, expiration).Err()
 }
 
-func (s *redisService) IsBlacklistedToken(token string) (bool, error) {
-	ctx := context.Background()
+func (s *redisService) IsBlacklistedToken(ctx context.Context, token string) (bool, error) {
 	val, err := s.client.Get(ctx, token).Result()
 	if err == redis.Nil {
 		return false, nil
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+type searchService struct {
+	searchRepo interfaces.SearchRepository
+}
+
+func NewSearchService(searchRepo interfaces.SearchRepository) interfaces.SearchService {
+	return &searchService{
+		searchRepo: searchRepo,
+	}
+}
+
+func (s *searchService) Search(ctx context.Context, query, kind string, opts models.ListOptions) ([]*models.SearchResult, string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, "", models.ErrEmptySearch
+	}
+
+	switch kind {
+	case "", "posts":
+		return s.searchRepo.SearchPosts(ctx, query, opts)
+	case "comments":
+		return s.searchRepo.SearchComments(ctx, query, opts)
+	default:
+		return nil, "", models.ErrInvalidSearchType
+	}
+}
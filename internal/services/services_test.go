@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/internal/repository"
+)
+
+// openTestDB returns a freshly migrated SQLite database. The repositories
+// are tested against Postgres too; here it's the service logic on top of
+// them that matters.
+func openTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	dsn := database.SQLiteDSN(filepath.Join(t.TempDir(), "blog.db"), "WAL", "NORMAL", 5*time.Second)
+	db, err := database.Connect(database.SQLite, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func createTestUser(t *testing.T, db *database.DB, username string) *models.User {
+	t.Helper()
+	user, err := repository.NewUserRepository(db).Create(context.Background(), &models.User{
+		FirstName: username,
+		LastName:  "Doe",
+		Username:  username,
+		Email:     username + "@example.com",
+		Password:  "password1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
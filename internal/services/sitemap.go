@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+type sitemapService struct {
+	sitemapRepo interfaces.SitemapRepository
+	ttl         time.Duration
+
+	mu       sync.Mutex
+	entries  []*models.SitemapEntry
+	loadedAt time.Time
+}
+
+// NewSitemapService caches the sitemap entries until a post changes or ttl
+// passes, whichever comes first. Subscribe HandleEvent to the event bus; the
+// ttl catches changes no event is published for, like deleted users.
+func NewSitemapService(sitemapRepo interfaces.SitemapRepository, ttl time.Duration) interfaces.SitemapService {
+	return &sitemapService{
+		sitemapRepo: sitemapRepo,
+		ttl:         ttl,
+	}
+}
+
+func (s *sitemapService) GetEntries(ctx context.Context) ([]*models.SitemapEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries != nil && time.Since(s.loadedAt) < s.ttl {
+		return s.entries, nil
+	}
+	entries, err := s.sitemapRepo.GetEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*models.SitemapEntry{}
+	}
+	s.entries, s.loadedAt = entries, time.Now()
+	return entries, nil
+}
+
+func (s *sitemapService) HandleEvent(ctx context.Context, event *models.Event) error {
+	switch event.Type {
+	case models.EventPostPublished, models.EventPostUpdated, models.EventPostDeleted, models.EventPostUnpublished:
+		s.mu.Lock()
+		s.entries = nil
+		s.mu.Unlock()
+	}
+	return nil
+}
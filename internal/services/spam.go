@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// The classifier stays quiet until it has seen this many spam and ham
+	// comments each.
+	minTrainingDocuments = 5
+	maxSpamTokens        = 200
+	spamStatsTopTokens   = 20
+)
+
+type spamService struct {
+	spamRepo        interfaces.SpamRepository
+	commentRepo     interfaces.CommentRepository
+	txManager       interfaces.TxManager
+	threshold       float64
+	reviewThreshold float64
+	checks          []interfaces.SpamCheck
+}
+
+// NewSpamService runs every check on new comments. A comment scoring at least
+// threshold is marked as spam and one scoring at least reviewThreshold is held
+// for moderation.
+func NewSpamService(spamRepo interfaces.SpamRepository, commentRepo interfaces.CommentRepository, txManager interfaces.TxManager, threshold, reviewThreshold float64, checks ...interfaces.SpamCheck) interfaces.SpamService {
+	return &spamService{
+		spamRepo:        spamRepo,
+		commentRepo:     commentRepo,
+		txManager:       txManager,
+		threshold:       threshold,
+		reviewThreshold: reviewThreshold,
+		checks:          checks,
+	}
+}
+
+func (s *spamService) Check(ctx context.Context, comment *models.Comment) (*models.SpamReport, error) {
+	report := &models.SpamReport{Verdict: models.SpamVerdictHam}
+	for _, check := range s.checks {
+		result, err := check.Check(ctx, comment)
+		if err != nil {
+			return nil, fmt.Errorf("spam check %s: %w", check.Name(), err)
+		}
+		report.Results = append(report.Results, result)
+		report.Score = math.Max(report.Score, result.Score)
+	}
+	switch {
+	case report.Score >= s.threshold:
+		report.Verdict = models.SpamVerdictSpam
+	case report.Score >= s.reviewThreshold:
+		report.Verdict = models.SpamVerdictReview
+	}
+	return report, nil
+}
+
+// InspectComment runs the pipeline again on a stored comment, so moderators
+// can see why it scored the way it did.
+func (s *spamService) InspectComment(ctx context.Context, commentId uuid.UUID) (*models.SpamReport, error) {
+	comment, err := s.commentRepo.GetByID(ctx, commentId)
+	if err != nil {
+		return nil, err
+	}
+	return s.Check(ctx, comment)
+}
+
+// Learn teaches the classifier that the comments are spam or ham. Comments it
+// already learned with the other label are unlearned first.
+func (s *spamService) Learn(ctx context.Context, comments []*models.Comment, spam bool) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		for _, comment := range comments {
+			if err := s.learn(ctx, comment.ID, comment.Content, spam); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *spamService) learn(ctx context.Context, commentId uuid.UUID, content string, spam bool) error {
+	label := spamLabel(spam)
+	previous, previousTokens, err := s.spamRepo.GetTraining(ctx, commentId)
+	if err != nil {
+		return err
+	}
+	if previous == label {
+		return nil
+	}
+	if previous != "" {
+		if err := s.spamRepo.AdjustTokens(ctx, previousTokens, previous == models.SpamVerdictSpam, -1); err != nil {
+			return err
+		}
+	}
+	tokens := spamTokens(content)
+	if err := s.spamRepo.AdjustTokens(ctx, tokens, spam, 1); err != nil {
+		return err
+	}
+	return s.spamRepo.SaveTraining(ctx, commentId, label, tokens)
+}
+
+// Retrain throws the model away and learns again from every comment that is
+// currently marked as spam or approved.
+func (s *spamService) Retrain(ctx context.Context) (*models.SpamStats, error) {
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		samples, err := s.spamRepo.GetTrainingSet(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.spamRepo.Reset(ctx); err != nil {
+			return err
+		}
+		for _, sample := range samples {
+			if err := s.learn(ctx, sample.CommentID, sample.Content, sample.Spam); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStats(ctx)
+}
+
+func (s *spamService) GetStats(ctx context.Context) (*models.SpamStats, error) {
+	return s.spamRepo.GetStats(ctx, spamStatsTopTokens)
+}
+
+type bayesCheck struct {
+	spamRepo interfaces.SpamRepository
+}
+
+// NewBayesCheck scores comments with a naive Bayes classifier trained on the
+// comments moderators marked as spam or approved.
+func NewBayesCheck(spamRepo interfaces.SpamRepository) interfaces.SpamCheck {
+	return &bayesCheck{spamRepo: spamRepo}
+}
+
+func (c *bayesCheck) Name() string { return "classifier" }
+
+func (c *bayesCheck) Check(ctx context.Context, comment *models.Comment) (models.SpamCheckResult, error) {
+	result := models.SpamCheckResult{Check: c.Name()}
+	stats, err := c.spamRepo.GetStats(ctx, 0)
+	if err != nil {
+		return result, err
+	}
+	if stats.SpamDocuments < minTrainingDocuments || stats.HamDocuments < minTrainingDocuments {
+		result.Reason = fmt.Sprintf("not enough training data (%d spam, %d ham)", stats.SpamDocuments, stats.HamDocuments)
+		return result, nil
+	}
+
+	counts, err := c.spamRepo.GetTokens(ctx, spamTokens(comment.Content))
+	if err != nil {
+		return result, err
+	}
+	spamDocs, hamDocs := float64(stats.SpamDocuments), float64(stats.HamDocuments)
+	logOdds := math.Log(spamDocs / hamDocs)
+	for _, token := range counts {
+		if token.Spam+token.Ham <= 0 {
+			continue
+		}
+		// Laplace smoothing keeps tokens seen in only one class finite.
+		pSpam := (float64(token.Spam) + 1) / (spamDocs + 2)
+		pHam := (float64(token.Ham) + 1) / (hamDocs + 2)
+		logOdds += math.Log(pSpam) - math.Log(pHam)
+	}
+	result.Score = 1 / (1 + math.Exp(-logOdds))
+	result.Reason = fmt.Sprintf("%d known tokens", len(counts))
+	return result, nil
+}
+
+// spamTokens splits content into the distinct lower case words the
+// classifier counts.
+func spamTokens(content string) []string {
+	seen := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var tokens []string
+	for _, word := range words {
+		if n := utf8.RuneCountInString(word); n < 2 || n > 40 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		tokens = append(tokens, word)
+		if len(tokens) == maxSpamTokens {
+			break
+		}
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+func spamLabel(spam bool) string {
+	if spam {
+		return models.SpamVerdictSpam
+	}
+	return models.SpamVerdictHam
+}
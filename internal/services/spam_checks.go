@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)https?://|www\.`)
+
+type linkCheck struct {
+	maxLinks int
+}
+
+// NewLinkCheck flags comments with more than maxLinks links. Fewer links
+// still raise the score a little, but never past the review threshold.
+func NewLinkCheck(maxLinks int) interfaces.SpamCheck {
+	return &linkCheck{maxLinks: maxLinks}
+}
+
+func (c *linkCheck) Name() string { return "links" }
+
+func (c *linkCheck) Check(ctx context.Context, comment *models.Comment) (models.SpamCheckResult, error) {
+	links := len(linkPattern.FindAllStringIndex(comment.Content, -1))
+	result := models.SpamCheckResult{Check: c.Name(), Reason: fmt.Sprintf("%d links (limit %d)", links, c.maxLinks)}
+	if links > c.maxLinks {
+		result.Score = 1
+	} else if links > 0 {
+		result.Score = 0.4 * float64(links) / float64(c.maxLinks+1)
+	}
+	return result, nil
+}
+
+type blockedWordsCheck struct {
+	words []string
+}
+
+// NewBlockedWordsCheck flags comments containing any of the words or phrases,
+// ignoring case.
+func NewBlockedWordsCheck(words []string) interfaces.SpamCheck {
+	lower := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			lower = append(lower, word)
+		}
+	}
+	return &blockedWordsCheck{words: lower}
+}
+
+func (c *blockedWordsCheck) Name() string { return "blocked_words" }
+
+func (c *blockedWordsCheck) Check(ctx context.Context, comment *models.Comment) (models.SpamCheckResult, error) {
+	content := strings.ToLower(comment.Content)
+	for _, word := range c.words {
+		if strings.Contains(content, word) {
+			return models.SpamCheckResult{Check: c.Name(), Score: 1, Reason: fmt.Sprintf("contains %q", word)}, nil
+		}
+	}
+	return models.SpamCheckResult{Check: c.Name(), Reason: "no blocked words"}, nil
+}
+
+type velocityCheck struct {
+	commentRepo interfaces.CommentRepository
+	limit       int
+	window      time.Duration
+}
+
+// NewVelocityCheck flags users who already wrote limit comments within window
+// before this one. A limit of 0 disables the check.
+func NewVelocityCheck(commentRepo interfaces.CommentRepository, limit int, window time.Duration) interfaces.SpamCheck {
+	return &velocityCheck{commentRepo: commentRepo, limit: limit, window: window}
+}
+
+func (c *velocityCheck) Name() string { return "velocity" }
+
+func (c *velocityCheck) Check(ctx context.Context, comment *models.Comment) (models.SpamCheckResult, error) {
+	result := models.SpamCheckResult{Check: c.Name()}
+	if c.limit <= 0 {
+		result.Reason = "disabled"
+		return result, nil
+	}
+	// A stored comment is checked against the comments written before it.
+	at := comment.CreatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	count, err := c.commentRepo.CountByUserSince(ctx, comment.UserID, at.Add(-c.window), at)
+	if err != nil {
+		return result, err
+	}
+	result.Reason = fmt.Sprintf("%d comments in %s (limit %d)", count, c.window, c.limit)
+	if count >= c.limit {
+		result.Score = 1
+	}
+	return result, nil
+}
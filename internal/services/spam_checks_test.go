@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/internal/repository"
+)
+
+func TestLinkCheck(t *testing.T) {
+	check := NewLinkCheck(2)
+	tests := []struct {
+		content string
+		want    float64
+	}{
+		{"no links here", 0},
+		{"see https://example.com", 0.4 / 3},
+		{"see HTTP://a.com and www.b.com", 0.8 / 3},
+		{"http://a.com http://b.com https://c.com", 1},
+	}
+	for _, tt := range tests {
+		result, err := check.Check(context.Background(), &models.Comment{Content: tt.content})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(result.Score-tt.want) > 1e-9 {
+			t.Errorf("%q scored %.3f, want %.3f", tt.content, result.Score, tt.want)
+		}
+	}
+}
+
+func TestBlockedWordsCheck(t *testing.T) {
+	check := NewBlockedWordsCheck([]string{" Casino ", "", "free money"})
+	tests := []struct {
+		content string
+		want    float64
+	}{
+		{"Best CASINO in town", 1},
+		{"Get FREE MONEY today", 1},
+		{"free and money, but apart", 0},
+		{"a casual comment", 0},
+	}
+	for _, tt := range tests {
+		result, err := check.Check(context.Background(), &models.Comment{Content: tt.content})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Score != tt.want {
+			t.Errorf("%q scored %v (%s), want %v", tt.content, result.Score, result.Reason, tt.want)
+		}
+	}
+}
+
+func TestVelocityCheck(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	commentRepo := repository.NewCommentRepository(db)
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	post, err := repository.NewPostRepository(db).Create(ctx, &models.Post{
+		Title:   "A post",
+		Content: "Content",
+		Status:  models.PostStatusPublished,
+		UserID:  alice.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored []*models.Comment
+	for range 3 {
+		comment, err := commentRepo.Create(ctx, &models.Comment{Content: "hello", PostID: post.ID, UserID: bob.ID, Status: models.CommentStatusApproved})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored = append(stored, comment)
+	}
+
+	tests := []struct {
+		name    string
+		limit   int
+		window  time.Duration
+		comment *models.Comment
+		want    float64
+	}{
+		{"at the limit", 3, time.Hour, &models.Comment{UserID: bob.ID}, 1},
+		{"under the limit", 4, time.Hour, &models.Comment{UserID: bob.ID}, 0},
+		{"other user", 1, time.Hour, &models.Comment{UserID: alice.ID}, 0},
+		{"disabled", 0, time.Hour, &models.Comment{UserID: bob.ID}, 0},
+		// A stored comment only counts the ones written before it.
+		{"stored comment", 3, time.Hour, stored[2], 0},
+		{"stored comment at the limit", 2, time.Hour, stored[2], 1},
+		{"window passed", 1, time.Hour, &models.Comment{UserID: bob.ID, CreatedAt: time.Now().Add(2 * time.Hour)}, 0},
+	}
+	for _, tt := range tests {
+		result, err := NewVelocityCheck(commentRepo, tt.limit, tt.window).Check(ctx, tt.comment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Score != tt.want {
+			t.Errorf("%s: scored %v (%s), want %v", tt.name, result.Score, result.Reason, tt.want)
+		}
+	}
+}
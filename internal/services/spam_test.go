@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ahmetilboga2004/go-blog/config/database"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	spamSamples = []string{
+		"Buy cheap pills online now",
+		"Cheap pills, best prices, buy now",
+		"Win a free prize, click now",
+		"Cheap loans approved now, click here",
+		"Buy followers cheap, click now",
+	}
+	hamSamples = []string{
+		"Great article about Go generics",
+		"I disagree with the second point about interfaces",
+		"Thanks, the example about channels helped",
+		"Could you write more about error handling in Go?",
+		"The benchmark section was really useful",
+	}
+)
+
+// fixedCheck is a spam check that always gives the same score.
+type fixedCheck float64
+
+func (c fixedCheck) Name() string { return "fixed" }
+
+func (c fixedCheck) Check(ctx context.Context, comment *models.Comment) (models.SpamCheckResult, error) {
+	return models.SpamCheckResult{Check: c.Name(), Score: float64(c)}, nil
+}
+
+func newTestSpamService(db *database.DB, checks ...interfaces.SpamCheck) interfaces.SpamService {
+	return NewSpamService(repository.NewSpamRepository(db), repository.NewCommentRepository(db), repository.NewTxManager(db), 0.9, 0.5, checks...)
+}
+
+// learn trains the classifier on contents, one made up comment each.
+func learn(t *testing.T, s interfaces.SpamService, contents []string, spam bool) {
+	t.Helper()
+	comments := make([]*models.Comment, len(contents))
+	for i, content := range contents {
+		comments[i] = &models.Comment{ID: uuid.New(), Content: content}
+	}
+	if err := s.Learn(context.Background(), comments, spam); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpamServiceVerdict(t *testing.T) {
+	db := openTestDB(t)
+	tests := []struct {
+		scores []float64
+		want   string
+	}{
+		{nil, models.SpamVerdictHam},
+		{[]float64{0.1, 0.4}, models.SpamVerdictHam},
+		{[]float64{0.1, 0.5}, models.SpamVerdictReview},
+		{[]float64{0.95, 0.2}, models.SpamVerdictSpam},
+	}
+	for _, tt := range tests {
+		var checks []interfaces.SpamCheck
+		for _, score := range tt.scores {
+			checks = append(checks, fixedCheck(score))
+		}
+		report, err := newTestSpamService(db, checks...).Check(context.Background(), &models.Comment{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Verdict != tt.want || len(report.Results) != len(tt.scores) {
+			t.Errorf("scores %v: verdict = %s with %d results, want %s", tt.scores, report.Verdict, len(report.Results), tt.want)
+		}
+	}
+}
+
+func TestBayesCheckColdStart(t *testing.T) {
+	db := openTestDB(t)
+	s := newTestSpamService(db)
+	check := NewBayesCheck(repository.NewSpamRepository(db))
+
+	// One spam comment short of the minimum, the classifier must not score
+	// anything however spammy it looks.
+	learn(t, s, spamSamples[:minTrainingDocuments-1], true)
+	learn(t, s, hamSamples[:minTrainingDocuments], false)
+	result, err := check.Check(context.Background(), &models.Comment{Content: spamSamples[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Score != 0 || !strings.Contains(result.Reason, "not enough training data") {
+		t.Errorf("cold start result = %v (%s), want no score", result.Score, result.Reason)
+	}
+}
+
+func TestBayesCheck(t *testing.T) {
+	db := openTestDB(t)
+	s := newTestSpamService(db)
+	learn(t, s, spamSamples, true)
+	learn(t, s, hamSamples, false)
+	check := NewBayesCheck(repository.NewSpamRepository(db))
+
+	tests := []struct {
+		content string
+		spam    bool
+	}{
+		{"Click now for cheap pills", true},
+		{"Buy now, cheap", true},
+		{"A useful article about Go interfaces", false},
+		{"Thanks for the example about error handling", false},
+	}
+	for _, tt := range tests {
+		result, err := check.Check(context.Background(), &models.Comment{Content: tt.content})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spam := result.Score > 0.5; spam != tt.spam {
+			t.Errorf("%q scored %.3f, want spam %v", tt.content, result.Score, tt.spam)
+		}
+	}
+
+	// Words the classifier never saw leave the prior, which is even here.
+	result, err := check.Check(context.Background(), &models.Comment{Content: "zebra quartz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Score != 0.5 {
+		t.Errorf("unknown words scored %.3f, want 0.5", result.Score)
+	}
+}
+
+func TestSpamLearnRelabels(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	spamRepo := repository.NewSpamRepository(db)
+	s := newTestSpamService(db)
+	comment := &models.Comment{ID: uuid.New(), Content: "cheap pills"}
+
+	counts := func() string {
+		t.Helper()
+		stats, err := s.GetStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens, err := spamRepo.GetTokens(ctx, []string{"cheap", "pills"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fmt.Sprintf("docs %d/%d cheap %d/%d pills %d/%d", stats.SpamDocuments, stats.HamDocuments,
+			tokens["cheap"].Spam, tokens["cheap"].Ham, tokens["pills"].Spam, tokens["pills"].Ham)
+	}
+
+	steps := []struct {
+		spam bool
+		want string
+	}{
+		{true, "docs 1/0 cheap 1/0 pills 1/0"},
+		// Learning the same label again must not count the comment twice.
+		{true, "docs 1/0 cheap 1/0 pills 1/0"},
+		// A new label unlearns the old one first.
+		{false, "docs 0/1 cheap 0/1 pills 0/1"},
+		{true, "docs 1/0 cheap 1/0 pills 1/0"},
+	}
+	for i, step := range steps {
+		if err := s.Learn(ctx, []*models.Comment{comment}, step.spam); err != nil {
+			t.Fatal(err)
+		}
+		if got := counts(); got != step.want {
+			t.Errorf("step %d: %s, want %s", i, got, step.want)
+		}
+	}
+}
+
+func TestSpamTokens(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"", nil},
+		{"Buy NOW, buy now!", []string{"buy", "now"}},
+		{"a b go", []string{"go"}},
+		{"visit http://example.com/x", []string{"com", "example", "http", "visit"}},
+		{"Çok güzel yazı", []string{"güzel", "yazı", "çok"}},
+		{strings.Repeat("x", 41) + " ok", []string{"ok"}},
+	}
+	for _, tt := range tests {
+		if got := spamTokens(tt.content); !slices.Equal(got, tt.want) {
+			t.Errorf("spamTokens(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -12,22 +13,24 @@ import (
 
 type userService struct {
 	userRepo     interfaces.UserRepository
+	followRepo   interfaces.FollowRepository
 	jwtService   interfaces.JWTService
 	redisService interfaces.RedisService
 	// mailService interfaces.MailService
 }
 
 // mailService interfaces.MailService
-func NewUserService(userRepo interfaces.UserRepository, jwtService interfaces.JWTService, redisService interfaces.RedisService) interfaces.UserService {
+func NewUserService(userRepo interfaces.UserRepository, followRepo interfaces.FollowRepository, jwtService interfaces.JWTService, redisService interfaces.RedisService) interfaces.UserService {
 	return &userService{
 		userRepo:     userRepo,
+		followRepo:   followRepo,
 		jwtService:   jwtService,
 		redisService: redisService,
 	}
 }
 
-func (s *userService) RegisterUser(user *models.User) (*models.User, error) {
-	existingUser, err := s.userRepo.FindByUsernameOrEmail(user.Username, user.Email)
+func (s *userService) RegisterUser(ctx context.Context, user *models.User) (*models.User, error) {
+	existingUser, err := s.userRepo.FindByUsernameOrEmail(ctx, user.Username, user.Email)
 	if err != nil {
 		return nil, err
 	}
@@ -35,15 +38,15 @@ func (s *userService) RegisterUser(user *models.User) (*models.User, error) {
 		return nil, errors.New("username or email already taken")
 	}
 
-	user, err = s.userRepo.Create(user)
+	user, err = s.userRepo.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (s *userService) LoginUser(usernameOrEmail, password string) (string, error) {
-	user, err := s.userRepo.FindByUsernameOrEmail(usernameOrEmail, usernameOrEmail)
+func (s *userService) LoginUser(ctx context.Context, usernameOrEmail, password string) (string, error) {
+	user, err := s.userRepo.FindByUsernameOrEmail(ctx, usernameOrEmail, usernameOrEmail)
 	if err != nil || user == nil {
 		return "", errors.New("invalid username or email")
 	}
@@ -58,7 +61,7 @@ func (s *userService) LoginUser(usernameOrEmail, password string) (string, error
 	return token, nil
 }
 
-func (s *userService) LogoutUser(token string) error {
+func (s *userService) LogoutUser(ctx context.Context, token string) error {
 	claims, err := s.jwtService.ParseTokenClaims(token)
 	if err != nil {
 		return errors.New("invalid token")
@@ -73,21 +76,65 @@ func (s *userService) LogoutUser(token string) error {
 		return errors.New("token expiration failed")
 	}
 
-	return s.redisService.BlacklistToken(token, expiration)
+	return s.redisService.BlacklistToken(ctx, token, expiration)
 }
 
-func (s *userService) GetAllUsers() ([]*models.User, error) {
-	users, err := s.userRepo.GetAll()
+func (s *userService) GetAllUsers(ctx context.Context, opts models.ListOptions) ([]*models.User, string, error) {
+	users, next, err := s.userRepo.GetAll(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return users, next, nil
+}
+
+func (s *userService) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	return users, nil
+	return user, nil
 }
 
-func (s *userService) GetUserByID(id uuid.UUID) (*models.User, error) {
-	user, err := s.userRepo.GetByID(id)
+// GetUserProfile returns the user with their follower and following counts
+// and whether the viewer follows them.
+func (s *userService) GetUserProfile(ctx context.Context, viewerId, id uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	user.FollowerCount, user.FollowingCount, err = s.followRepo.GetCounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if viewerId != uuid.Nil && viewerId != id {
+		user.IsFollowing, err = s.followRepo.IsFollowing(ctx, viewerId, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// DeleteUser removes the account. The database keeps the user's posts and
+// comments but clears their author.
+func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	return s.userRepo.Delete(ctx, id)
+}
+
+func (s *userService) SetUserRole(ctx context.Context, usernameOrEmail, role string) (*models.User, error) {
+	if role != models.RoleUser && role != models.RoleModerator && role != models.RoleAdmin {
+		return nil, errors.New("invalid role")
+	}
+	user, err := s.userRepo.FindByUsernameOrEmail(ctx, usernameOrEmail, usernameOrEmail)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if err := s.userRepo.UpdateRole(ctx, user.ID, role); err != nil {
+		return nil, err
+	}
+	user.Role = role
 	return user, nil
 }
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/dto"
+	"github.com/ahmetilboga2004/go-blog/internal/interfaces"
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// webhookBatchSize is how many due deliveries one DeliverDue call sends,
+	// webhookWorkers how many of them at once.
+	webhookBatchSize = 50
+	webhookWorkers   = 8
+	// maxResponseBody is how much of the receiver's response is logged.
+	maxResponseBody = 1024
+	// maxRetryDelay caps the wait between delivery attempts.
+	maxRetryDelay = 24 * time.Hour
+)
+
+type webhookService struct {
+	webhookRepo  interfaces.WebhookRepository
+	deliveryRepo interfaces.WebhookDeliveryRepository
+	userRepo     interfaces.UserRepository
+	client       *http.Client
+	timeout      time.Duration
+	maxAttempts  int
+	backoff      time.Duration
+}
+
+// NewWebhookService queues deliveries for the webhooks subscribed to an event
+// and sends them from DeliverDue. Each request times out after timeout; a
+// failed delivery is retried up to maxAttempts times in all, waiting backoff
+// and then twice as long after each failure, up to a day. Unless
+// allowPrivate is set, webhooks can't call loopback or private network
+// addresses.
+func NewWebhookService(webhookRepo interfaces.WebhookRepository, deliveryRepo interfaces.WebhookDeliveryRepository, userRepo interfaces.UserRepository, timeout time.Duration, maxAttempts int, backoff time.Duration, allowPrivate bool) interfaces.WebhookService {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = publicAddressesOnly
+	}
+	return &webhookService{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		userRepo:     userRepo,
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext: dialer.DialContext,
+				// No proxy: through one, the dialer would only check the
+				// proxy's address and not the webhook's.
+				Proxy: nil,
+			},
+		},
+		timeout:     timeout,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+	}
+}
+
+// publicAddressesOnly is checked after DNS resolution, so host names
+// pointing at internal addresses are refused too.
+func publicAddressesOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		return fmt.Errorf("webhooks can't call %s", host)
+	}
+	return nil
+}
+
+func (s *webhookService) GetWebhooks(ctx context.Context, userId uuid.UUID) ([]*models.Webhook, error) {
+	return s.webhookRepo.GetByUserID(ctx, userId)
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, userId, id uuid.UUID) (*models.Webhook, error) {
+	return s.ownWebhook(ctx, userId, id)
+}
+
+// CreateWebhook generates a secret when none is given.
+func (s *webhookService) CreateWebhook(ctx context.Context, userId uuid.UUID, webhook *models.Webhook) (*models.Webhook, error) {
+	if err := s.check(ctx, userId, webhook); err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+	webhook.UserID = userId
+	return s.webhookRepo.Create(ctx, webhook)
+}
+
+// UpdateWebhook keeps the current secret when none is given.
+func (s *webhookService) UpdateWebhook(ctx context.Context, userId, id uuid.UUID, webhook *models.Webhook) (*models.Webhook, error) {
+	current, err := s.ownWebhook(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(ctx, userId, webhook); err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = current.Secret
+	}
+	return s.webhookRepo.Update(ctx, id, webhook)
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, userId, id uuid.UUID) error {
+	if _, err := s.ownWebhook(ctx, userId, id); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+// check validates the URL and events of a webhook. Only admins may create
+// global webhooks.
+func (s *webhookService) check(ctx context.Context, userId uuid.UUID, webhook *models.Webhook) error {
+	target, err := url.Parse(webhook.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("webhook URL must be an absolute http or https URL")
+	}
+	if len(webhook.Events) == 0 {
+		return errors.New("webhook must subscribe to at least one event")
+	}
+	for _, event := range webhook.Events {
+		if !slices.Contains(models.WebhookEvents, event) {
+			return fmt.Errorf("unknown webhook event: %s", event)
+		}
+	}
+	if webhook.Global {
+		user, err := s.userRepo.GetByID(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if user.Role != models.RoleAdmin {
+			return errors.New("only admins can create global webhooks")
+		}
+	}
+	return nil
+}
+
+func (s *webhookService) ownWebhook(ctx context.Context, userId, id uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.UserID != userId {
+		return nil, errors.New("webhook not found")
+	}
+	return webhook, nil
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, userId, webhookId uuid.UUID, opts models.ListOptions) ([]*models.WebhookDelivery, string, error) {
+	if _, err := s.ownWebhook(ctx, userId, webhookId); err != nil {
+		return nil, "", err
+	}
+	return s.deliveryRepo.GetByWebhookID(ctx, webhookId, opts)
+}
+
+func (s *webhookService) GetDelivery(ctx context.Context, userId, webhookId, id uuid.UUID) (*models.WebhookDelivery, error) {
+	if _, err := s.ownWebhook(ctx, userId, webhookId); err != nil {
+		return nil, err
+	}
+	delivery, err := s.deliveryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.WebhookID != webhookId {
+		return nil, errors.New("delivery not found")
+	}
+	return delivery, nil
+}
+
+// Redeliver queues a new delivery with the same payload, keeping the old one
+// in the log.
+func (s *webhookService) Redeliver(ctx context.Context, userId, webhookId, id uuid.UUID) (*models.WebhookDelivery, error) {
+	delivery, err := s.GetDelivery(ctx, userId, webhookId, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.Create(ctx, &models.WebhookDelivery{
+		WebhookID: webhookId,
+		Event:     delivery.Event,
+		Payload:   delivery.Payload,
+	})
+}
+
+// Enqueue queues the event for the webhooks of the post's author and the
+// global ones. It must run in the transaction that makes the change, so the
+// deliveries are stored only if the change commits. Events webhooks can't
+// subscribe to are ignored.
+func (s *webhookService) Enqueue(ctx context.Context, event *models.Event) error {
+	if !slices.Contains(models.WebhookEvents, event.Type) {
+		return nil
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	webhooks, err := s.webhookRepo.GetSubscribed(ctx, event.Type, event.Post.UserID)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(webhookPayload(event))
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		_, err := s.deliveryRepo.Create(ctx, &models.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Event:     event.Type,
+			Payload:   string(payload),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webhookPayload is the body posted for event.
+func webhookPayload(event *models.Event) *dto.WebhookPayload {
+	payload := &dto.WebhookPayload{
+		Event:      event.Type,
+		OccurredAt: event.OccurredAt,
+		Post:       dto.FromPost(event.Post),
+	}
+	if event.Comment != nil {
+		payload.Comment = dto.CommentResponseFromModel(event.Comment)
+	}
+	return payload
+}
+
+// DeliverDue sends the deliveries whose next attempt is due. Every delivery
+// is claimed first, so several instances can share the queue. A delivery is
+// only claimed once a worker is free to send it, so its lease starts when the
+// attempt does rather than when the batch was fetched.
+func (s *webhookService) DeliverDue(ctx context.Context) error {
+	deliveries, err := s.deliveryRepo.GetDue(ctx, time.Now().UTC(), webhookBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, webhookWorkers)
+	for _, delivery := range deliveries {
+		workers <- struct{}{}
+		now := time.Now().UTC()
+		claimed, err := s.deliveryRepo.Claim(ctx, delivery, now, now.Add(2*s.timeout))
+		if err != nil {
+			<-workers
+			wg.Wait()
+			return err
+		}
+		if !claimed {
+			<-workers
+			continue
+		}
+		wg.Add(1)
+		go func(delivery *models.WebhookDelivery) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			if err := s.attempt(ctx, delivery); err != nil {
+				utils.Log(utils.WARNING, "Failed to record webhook delivery %s: %v", delivery.ID, err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+	return nil
+}
+
+// attempt sends a delivery once and records the outcome. Any 2xx response
+// counts as delivered.
+func (s *webhookService) attempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	webhook, err := s.webhookRepo.GetByID(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus, delivery.ResponseBody, delivery.Error = 0, "", ""
+	if webhook.Active {
+		delivery.ResponseStatus, delivery.ResponseBody, err = s.send(ctx, webhook, delivery)
+		if err == nil && delivery.ResponseStatus/100 != 2 {
+			err = fmt.Errorf("receiver responded with %d", delivery.ResponseStatus)
+		}
+	} else {
+		err = errors.New("webhook is disabled")
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryStatusSucceeded
+	case delivery.Attempts >= s.maxAttempts || !webhook.Active:
+		delivery.Status = models.DeliveryStatusFailed
+		delivery.Error = err.Error()
+	default:
+		delivery.Error = err.Error()
+		delivery.NextAttemptAt = now.Add(retryDelay(s.backoff, delivery.Attempts))
+	}
+	return s.deliveryRepo.SaveAttempt(ctx, delivery)
+}
+
+// retryDelay doubles backoff for every attempt after the first, up to
+// maxRetryDelay.
+func retryDelay(backoff time.Duration, attempts int) time.Duration {
+	delay := backoff
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// send posts the payload signed with the webhook's secret, GitHub style: the
+// X-Webhook-Signature-256 header is "sha256=" and the hex HMAC-SHA256 of the
+// body.
+func (s *webhookService) send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, string, error) {
+	body := []byte(delivery.Payload)
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write(body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-blog-webhooks")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.String())
+	req.Header.Set("X-Webhook-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	return resp.StatusCode, string(responseBody), nil
+}
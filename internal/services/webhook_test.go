@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/ahmetilboga2004/go-blog/internal/repository"
+)
+
+// The example from GitHub's webhook documentation, which receivers are
+// likely to verify against.
+const (
+	signatureSecret  = "It's a Secret to Everybody"
+	signaturePayload = "Hello, World!"
+	signature        = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}))
+	defer server.Close()
+
+	s := NewWebhookService(nil, nil, nil, time.Second, 3, time.Minute, true).(*webhookService)
+	webhook := &models.Webhook{URL: server.URL, Secret: signatureSecret}
+	delivery := &models.WebhookDelivery{Event: models.EventPostPublished, Payload: signaturePayload}
+	status, _, err := s.send(context.Background(), webhook, delivery)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("send = %d, %v", status, err)
+	}
+	if header := got.Header.Get("X-Webhook-Signature-256"); header != signature {
+		t.Errorf("signature = %q, want %q", header, signature)
+	}
+	if event := got.Header.Get("X-Webhook-Event"); event != models.EventPostPublished {
+		t.Errorf("event header = %q", event)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		backoff  time.Duration
+		attempts int
+		want     time.Duration
+	}{
+		{time.Minute, 1, time.Minute},
+		{time.Minute, 2, 2 * time.Minute},
+		{time.Minute, 5, 16 * time.Minute},
+		{time.Minute, 11, 1024 * time.Minute},
+		{time.Minute, 12, maxRetryDelay},
+		{time.Minute, 1000, maxRetryDelay},
+		{48 * time.Hour, 1, maxRetryDelay},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.backoff, tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%v, %d) = %v, want %v", tt.backoff, tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookAttemptGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	db := openTestDB(t)
+	alice := createTestUser(t, db, "alice")
+	webhookRepo := repository.NewWebhookRepository(db)
+	deliveryRepo := repository.NewWebhookDeliveryRepository(db)
+	webhook, err := webhookRepo.Create(ctx, &models.Webhook{
+		UserID: alice.ID,
+		URL:    server.URL,
+		Secret: "secret",
+		Events: []string{models.EventPostPublished},
+		Active: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delivery, err := deliveryRepo.Create(ctx, &models.WebhookDelivery{WebhookID: webhook.ID, Event: models.EventPostPublished, Payload: "{}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const maxAttempts = 3
+	s := NewWebhookService(webhookRepo, deliveryRepo, nil, time.Second, maxAttempts, time.Minute, true).(*webhookService)
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		before := time.Now().UTC()
+		if err := s.attempt(ctx, delivery); err != nil {
+			t.Fatal(err)
+		}
+		saved, err := deliveryRepo.GetByID(ctx, delivery.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved.Attempts != attempt || saved.ResponseStatus != http.StatusInternalServerError {
+			t.Fatalf("attempt %d saved as attempt %d with status %d", attempt, saved.Attempts, saved.ResponseStatus)
+		}
+		if attempt < maxAttempts {
+			if saved.Status != models.DeliveryStatusPending {
+				t.Fatalf("attempt %d: status = %s, want a retry", attempt, saved.Status)
+			}
+			if wait := saved.NextAttemptAt.Sub(before); wait < retryDelay(time.Minute, attempt) {
+				t.Errorf("attempt %d: retried after %v, want %v", attempt, wait, retryDelay(time.Minute, attempt))
+			}
+			continue
+		}
+		if saved.Status != models.DeliveryStatusFailed || saved.Error == "" {
+			t.Errorf("last attempt: status = %s, error = %q, want it to give up", saved.Status, saved.Error)
+		}
+	}
+}
+
+func TestPublicAddressesOnly(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:4700:4700::1111]:443", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"10.0.0.1:80", false},
+		{"172.16.5.4:80", false},
+		{"192.168.1.1:8080", false},
+		{"[fd00::1]:80", false},
+		{"169.254.169.254:80", false},
+		{"[fe80::1]:80", false},
+		{"0.0.0.0:80", false},
+	}
+	for _, tt := range tests {
+		err := publicAddressesOnly("tcp", tt.address, nil)
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("publicAddressesOnly(%s) = %v, want allowed %v", tt.address, err, tt.allowed)
+		}
+	}
+
+	// The check runs in the dialer, so a webhook pointing at this machine is
+	// refused before anything is sent.
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+	s := NewWebhookService(nil, nil, nil, time.Second, 3, time.Minute, false).(*webhookService)
+	if _, _, err := s.send(context.Background(), &models.Webhook{URL: server.URL}, &models.WebhookDelivery{Payload: "{}"}); err == nil || called {
+		t.Errorf("webhook on a loopback address was called: %v", err)
+	}
+}
@@ -1,9 +1,15 @@
 package utils
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/ahmetilboga2004/go-blog/internal/middlewares"
 	"github.com/google/uuid"
@@ -15,6 +21,10 @@ type ErrorResponse struct {
 }
 
 func HandleError(w http.ResponseWriter, status int, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		status = http.StatusGatewayTimeout
+		err = errors.New("request timed out")
+	}
 	errorResponse := ErrorResponse{
 		Code:    status,
 		Message: err.Error(),
@@ -28,6 +38,17 @@ func ResponseJSON(w http.ResponseWriter, status int, data any) error {
 	return json.NewEncoder(w).Encode(data)
 }
 
+// ServeConditional writes body with an ETag hashed from it and, unless
+// modified is the zero time or the Unix epoch, a Last-Modified header, and
+// answers matching conditional requests with 304 Not Modified.
+func ServeConditional(w http.ResponseWriter, r *http.Request, contentType string, body []byte, modified time.Time) {
+	sum := sha256.Sum256(body)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, "", modified, bytes.NewReader(body))
+}
+
 func GetUserIDFromContext(r *http.Request) (uuid.UUID, error) {
 	rawUserID := r.Context().Value(middlewares.UserIDKey)
 
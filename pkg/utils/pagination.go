@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmetilboga2004/go-blog/internal/models"
+	"github.com/google/uuid"
+)
+
+// ParseListOptions reads the limit, cursor, sort and filter query parameters
+// shared by all list endpoints.
+func ParseListOptions(r *http.Request) (models.ListOptions, error) {
+	query := r.URL.Query()
+	var opts models.ListOptions
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return opts, fmt.Errorf("invalid limit: %s", limit)
+		}
+		opts.Limit = n
+	}
+
+	opts.Cursor = query.Get("cursor")
+
+	if sort := query.Get("sort"); sort != "" {
+		opts.Desc = strings.HasPrefix(sort, "-")
+		opts.Sort = strings.TrimPrefix(sort, "-")
+	}
+
+	var err error
+	if opts.AuthorID, err = parseUUIDParam(query.Get("author")); err != nil {
+		return opts, fmt.Errorf("invalid author: %w", err)
+	}
+	if opts.PostID, err = parseUUIDParam(query.Get("post")); err != nil {
+		return opts, fmt.Errorf("invalid post: %w", err)
+	}
+	opts.Tag = models.NormalizeTag(query.Get("tag"))
+	if opts.From, err = parseDateParam(query.Get("from")); err != nil {
+		return opts, fmt.Errorf("invalid from: %w", err)
+	}
+	if opts.To, err = parseDateParam(query.Get("to")); err != nil {
+		return opts, fmt.Errorf("invalid to: %w", err)
+	}
+
+	return opts, nil
+}
+
+// SetPaginationHeaders exposes the next cursor both as a Link header and as
+// X-Next-Cursor so clients can keep consuming a plain JSON array.
+func SetPaginationHeaders(w http.ResponseWriter, r *http.Request, nextCursor string) {
+	if nextCursor == "" {
+		return
+	}
+	next := *r.URL
+	query := next.Query()
+	query.Set("cursor", nextCursor)
+	next.RawQuery = query.Encode()
+
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+	w.Header().Set("X-Next-Cursor", nextCursor)
+}
+
+func parseUUIDParam(value string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(value)
+}
+
+func parseDateParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -0,0 +1,35 @@
+// Package web holds the themes of the HTML frontend. Each theme is a
+// directory with a templates directory of html/template files and a static
+// directory of assets served under /static/.
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+//go:embed themes
+var themes embed.FS
+
+// Theme returns the files of the named theme: from dir when it is set, so
+// themes can be changed without rebuilding, otherwise from the themes built
+// into the binary.
+func Theme(dir, name string) (fs.FS, error) {
+	if dir != "" {
+		theme := filepath.Join(dir, name)
+		if _, err := os.Stat(theme); err != nil {
+			return nil, err
+		}
+		return os.DirFS(theme), nil
+	}
+	theme, err := fs.Sub(themes, "themes/"+name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fs.Stat(theme, "templates"); err != nil {
+		return nil, err
+	}
+	return theme, nil
+}